@@ -0,0 +1,304 @@
+package catalogs
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+	"gopkg.in/yaml.v3"
+)
+
+// AdmissionPolicy is the rule set a catalog server must pass before it is
+// written to a catalog. Rules left at their zero value are not evaluated.
+type AdmissionPolicy struct {
+	// ApprovedRegistries lists the registries images may come from, e.g. docker.io
+	ApprovedRegistries []string `yaml:"approvedRegistries,omitempty" json:"approvedRegistries,omitempty"`
+	RequireDigest      bool     `yaml:"requireDigest,omitempty" json:"requireDigest,omitempty"`
+	ForbidLatestTag    bool     `yaml:"forbidLatestTag,omitempty" json:"forbidLatestTag,omitempty"`
+	RequireHTTPSRemote bool     `yaml:"requireHttpsRemote,omitempty" json:"requireHttpsRemote,omitempty"`
+	RequireIcon        bool     `yaml:"requireIcon,omitempty" json:"requireIcon,omitempty"`
+	RequireDescription bool     `yaml:"requireDescription,omitempty" json:"requireDescription,omitempty"`
+	ForbidPrivileged   bool     `yaml:"forbidPrivileged,omitempty" json:"forbidPrivileged,omitempty"`
+}
+
+// LoadAdmissionPolicy reads an admission policy YAML file
+func LoadAdmissionPolicy(path string) (*AdmissionPolicy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var policy AdmissionPolicy
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse admission policy %s: %w", path, err)
+	}
+	return &policy, nil
+}
+
+// RuleResult is the outcome of one admission rule
+type RuleResult struct {
+	Rule   string `json:"rule"`
+	Passed bool   `json:"passed"`
+	// Skipped is set when the rule does not apply, e.g. image rules on remotes
+	Skipped bool   `json:"skipped,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+func (r RuleResult) String() string {
+	status := "PASS"
+	switch {
+	case r.Skipped:
+		status = "SKIP"
+	case !r.Passed:
+		status = "FAIL"
+	}
+	if r.Message == "" {
+		return fmt.Sprintf("%s %s", status, r.Rule)
+	}
+	return fmt.Sprintf("%s %s: %s", status, r.Rule, r.Message)
+}
+
+// AdmissionResult holds the per-rule results for one server
+type AdmissionResult struct {
+	Server  string       `json:"server"`
+	Results []RuleResult `json:"results"`
+}
+
+// Admitted reports whether every evaluated rule passed
+func (r AdmissionResult) Admitted() bool {
+	for _, result := range r.Results {
+		if !result.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// Failures returns the rules that failed
+func (r AdmissionResult) Failures() []RuleResult {
+	var failures []RuleResult
+	for _, result := range r.Results {
+		if !result.Passed {
+			failures = append(failures, result)
+		}
+	}
+	return failures
+}
+
+// AdmissionError is returned when a server fails the admission policy
+type AdmissionError struct {
+	Result AdmissionResult
+}
+
+func (e *AdmissionError) Error() string {
+	var lines []string
+	for _, failure := range e.Result.Failures() {
+		lines = append(lines, failure.String())
+	}
+	return fmt.Sprintf("server %s rejected by admission policy:\n  %s", e.Result.Server, strings.Join(lines, "\n  "))
+}
+
+// imageReference is a parsed image name
+type imageReference struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+var digestPattern = regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)
+
+// parseImageReference splits an image like ghcr.io/owner/repo:tag@sha256:...
+// Images without a registry host come from docker.io. Older catalogs wrote
+// tags as identifier@version, so anything after @ that is not a sha256:
+// digest is the tag.
+func parseImageReference(image string) imageReference {
+	var ref imageReference
+
+	name := image
+	var suffix string
+	if i := strings.Index(name, "@"); i >= 0 {
+		name, suffix = name[:i], name[i+1:]
+	}
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, ref.Tag = name[:i], name[i+1:]
+	}
+	if strings.HasPrefix(suffix, "sha256:") {
+		ref.Digest = suffix
+	} else if suffix != "" && ref.Tag == "" {
+		ref.Tag = suffix
+	}
+
+	first, rest, found := strings.Cut(name, "/")
+	if found && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		ref.Registry, ref.Repository = first, rest
+	} else {
+		ref.Registry, ref.Repository = "docker.io", name
+	}
+	if ref.Registry == "index.docker.io" || ref.Registry == "registry-1.docker.io" {
+		ref.Registry = "docker.io"
+	}
+	return ref
+}
+
+type admissionRule struct {
+	name    string
+	enabled func(p *AdmissionPolicy) bool
+	// check returns whether the rule applies and, if it does, a failure
+	// message. flags are the docker run runtime arguments of the registry
+	// input, when it is known.
+	check func(p *AdmissionPolicy, server catalog.Server, flags []runtimeFlag) (applies bool, failure string)
+}
+
+// privilegedRuntimeFlags escape the container's isolation; the catalog has no
+// field for them, so they are only visible in the registry input
+var privilegedRuntimeFlags = map[string]bool{
+	"--privileged": true,
+	"--cap-add":    true,
+	"--device":     true,
+}
+
+var admissionRules = []admissionRule{
+	{
+		name:    "approved-registry",
+		enabled: func(p *AdmissionPolicy) bool { return len(p.ApprovedRegistries) > 0 },
+		check: func(p *AdmissionPolicy, server catalog.Server, flags []runtimeFlag) (bool, string) {
+			if server.Image == "" {
+				return false, ""
+			}
+			registry := parseImageReference(server.Image).Registry
+			for _, approved := range p.ApprovedRegistries {
+				if registry == approved {
+					return true, ""
+				}
+			}
+			return true, fmt.Sprintf("image %s comes from %s, which is not an approved registry", server.Image, registry)
+		},
+	},
+	{
+		name:    "digest-pinned",
+		enabled: func(p *AdmissionPolicy) bool { return p.RequireDigest },
+		check: func(p *AdmissionPolicy, server catalog.Server, flags []runtimeFlag) (bool, string) {
+			if server.Image == "" {
+				return false, ""
+			}
+			ref := parseImageReference(server.Image)
+			if !digestPattern.MatchString(ref.Digest) {
+				if ref.Tag != "" {
+					return true, fmt.Sprintf("image %s is pinned to tag %s, not a sha256 digest", server.Image, ref.Tag)
+				}
+				return true, fmt.Sprintf("image %s is not pinned to a sha256 digest", server.Image)
+			}
+			return true, ""
+		},
+	},
+	{
+		name:    "no-latest-tag",
+		enabled: func(p *AdmissionPolicy) bool { return p.ForbidLatestTag },
+		check: func(p *AdmissionPolicy, server catalog.Server, flags []runtimeFlag) (bool, string) {
+			if server.Image == "" {
+				return false, ""
+			}
+			ref := parseImageReference(server.Image)
+			if ref.Tag == "latest" || (ref.Tag == "" && ref.Digest == "") {
+				return true, fmt.Sprintf("image %s uses the latest tag", server.Image)
+			}
+			return true, ""
+		},
+	},
+	{
+		name:    "https-remote",
+		enabled: func(p *AdmissionPolicy) bool { return p.RequireHTTPSRemote },
+		check: func(p *AdmissionPolicy, server catalog.Server, flags []runtimeFlag) (bool, string) {
+			remote := remoteURL(server)
+			if remote == "" {
+				return false, ""
+			}
+			if u, err := url.Parse(withSamplePlaceholders(remote)); err != nil || u.Scheme != "https" {
+				return true, fmt.Sprintf("remote %s does not use https", remote)
+			}
+			return true, ""
+		},
+	},
+	{
+		name:    "icon",
+		enabled: func(p *AdmissionPolicy) bool { return p.RequireIcon },
+		check: func(p *AdmissionPolicy, server catalog.Server, flags []runtimeFlag) (bool, string) {
+			if strings.TrimSpace(server.Icon) == "" {
+				return true, "icon is missing"
+			}
+			return true, ""
+		},
+	},
+	{
+		name:    "description",
+		enabled: func(p *AdmissionPolicy) bool { return p.RequireDescription },
+		check: func(p *AdmissionPolicy, server catalog.Server, flags []runtimeFlag) (bool, string) {
+			if strings.TrimSpace(server.Description) == "" {
+				return true, "description is missing"
+			}
+			return true, ""
+		},
+	},
+	{
+		name:    "unprivileged",
+		enabled: func(p *AdmissionPolicy) bool { return p.ForbidPrivileged },
+		check: func(p *AdmissionPolicy, server catalog.Server, flags []runtimeFlag) (bool, string) {
+			if server.Image == "" {
+				return false, ""
+			}
+			var problems []string
+			if user := strings.SplitN(server.User, ":", 2)[0]; user == "root" || user == "0" {
+				problems = append(problems, fmt.Sprintf("runs as %s", server.User))
+			}
+			for _, volume := range server.Volumes {
+				source := strings.SplitN(volume, ":", 2)[0]
+				if source == "/" || strings.HasSuffix(source, "docker.sock") {
+					problems = append(problems, fmt.Sprintf("mounts %s", source))
+				}
+			}
+			for _, flag := range flags {
+				if privilegedRuntimeFlags[flag.Name] {
+					problems = append(problems, fmt.Sprintf("runs with %s", flag))
+				}
+			}
+			return true, strings.Join(problems, ", ")
+		},
+	},
+}
+
+// Evaluate runs every enabled rule against a catalog server
+func (p *AdmissionPolicy) Evaluate(server catalog.Server) AdmissionResult {
+	return p.evaluate(server, nil)
+}
+
+// EvaluateInput runs every enabled rule against a catalog server and the
+// registry entry it was converted from, whose runtime arguments may carry
+// flags the catalog server cannot, such as --privileged
+func (p *AdmissionPolicy) EvaluateInput(server catalog.Server, input ServerDetail) AdmissionResult {
+	var flags []runtimeFlag
+	if len(input.Packages) > 0 && input.Packages[0].RegistryType == model.RegistryTypeOCI {
+		flags, _ = splitRuntimeFlags(input.Packages[0].RuntimeArguments)
+	}
+	return p.evaluate(server, flags)
+}
+
+func (p *AdmissionPolicy) evaluate(server catalog.Server, flags []runtimeFlag) AdmissionResult {
+	result := AdmissionResult{Server: server.Name}
+	for _, rule := range admissionRules {
+		if !rule.enabled(p) {
+			continue
+		}
+		applies, failure := rule.check(p, server, flags)
+		result.Results = append(result.Results, RuleResult{
+			Rule:    rule.name,
+			Passed:  failure == "",
+			Skipped: !applies,
+			Message: failure,
+		})
+	}
+	return result
+}
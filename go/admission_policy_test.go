@@ -0,0 +1,173 @@
+package catalogs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+var strictAdmissionPolicy = &AdmissionPolicy{
+	ApprovedRegistries: []string{"docker.io", "ghcr.io"},
+	RequireDigest:      true,
+	ForbidLatestTag:    true,
+	RequireHTTPSRemote: true,
+	RequireIcon:        true,
+	RequireDescription: true,
+	ForbidPrivileged:   true,
+}
+
+func ruleResults(result AdmissionResult) map[string]RuleResult {
+	results := make(map[string]RuleResult)
+	for _, r := range result.Results {
+		results[r.Rule] = r
+	}
+	return results
+}
+
+func TestAdmissionPolicyImageServer(t *testing.T) {
+	result := strictAdmissionPolicy.Evaluate(catalog.Server{
+		Name:        "com-docker-knowledge-graph",
+		Type:        "server",
+		Image:       "quay.io/example/knowledge-graph:latest",
+		Description: "Knowledge graph",
+		User:        "root",
+		Volumes:     []string{"/var/run/docker.sock:/var/run/docker.sock"},
+	})
+
+	if result.Admitted() {
+		t.Fatal("Expected server to be rejected")
+	}
+
+	results := ruleResults(result)
+	for _, rule := range []string{"approved-registry", "digest-pinned", "no-latest-tag", "icon", "unprivileged"} {
+		if results[rule].Passed {
+			t.Errorf("Expected %s to fail", rule)
+		}
+	}
+	if !results["description"].Passed {
+		t.Error("Expected description to pass")
+	}
+	if !results["https-remote"].Skipped || !results["https-remote"].Passed {
+		t.Error("Expected https-remote to be skipped for image servers")
+	}
+}
+
+func TestAdmissionPolicyRemoteServer(t *testing.T) {
+	result := strictAdmissionPolicy.Evaluate(catalog.Server{
+		Name:        "com-example-insecure",
+		Type:        "remote",
+		Description: "Insecure remote",
+		Icon:        "https://example.com/icon.png",
+		Remote:      catalog.Remote{URL: "http://example.com/mcp"},
+	})
+
+	failures := result.Failures()
+	if len(failures) != 1 || failures[0].Rule != "https-remote" {
+		t.Errorf("Expected only https-remote to fail, got %+v", failures)
+	}
+}
+
+func TestAdmissionPolicyPinnedImage(t *testing.T) {
+	result := strictAdmissionPolicy.Evaluate(catalog.Server{
+		Name:        "io-github-slimslenderslacks-garmin_mcp",
+		Type:        "server",
+		Image:       "jimclark106/gramin_mcp@sha256:637379b17fc12103bb00a52ccf27368208fd8009e6efe2272b623b1a5431814a",
+		Description: "Garmin data",
+		Icon:        "https://example.com/icon.png",
+		User:        "1000:1000",
+	})
+
+	if !result.Admitted() {
+		t.Errorf("Expected pinned docker.io image to be admitted, got %+v", result.Failures())
+	}
+}
+
+func TestParseImageReference(t *testing.T) {
+	tests := []struct {
+		image string
+		want  imageReference
+	}{
+		{"jimclark106/knowledge-graph:latest", imageReference{Registry: "docker.io", Repository: "jimclark106/knowledge-graph", Tag: "latest"}},
+		{"docker.io/mcp/filesystem@sha256:abc", imageReference{Registry: "docker.io", Repository: "mcp/filesystem", Digest: "sha256:abc"}},
+		{"ghcr.io/owner/repo:v1@sha256:abc", imageReference{Registry: "ghcr.io", Repository: "owner/repo", Tag: "v1", Digest: "sha256:abc"}},
+		{"localhost:5000/repo", imageReference{Registry: "localhost:5000", Repository: "repo"}},
+		{"docker.io/example/notes@1.0.0", imageReference{Registry: "docker.io", Repository: "example/notes", Tag: "1.0.0"}},
+	}
+
+	for _, tt := range tests {
+		if got := parseImageReference(tt.image); got != tt.want {
+			t.Errorf("parseImageReference(%q) = %+v, want %+v", tt.image, got, tt.want)
+		}
+	}
+}
+
+func TestTransformAdmissionGate(t *testing.T) {
+	registryJSON := `{
+		"server": {
+			"name": "io.github.example/unpinned",
+			"description": "Unpinned server",
+			"version": "1.0.0",
+			"packages": [
+				{
+					"registryType": "oci",
+					"identifier": "docker.io/example/unpinned",
+					"version": "1.0.0",
+					"transport": {"type": "stdio"}
+				}
+			]
+		}
+	}`
+
+	_, err := TransformJSONWithOptions(registryJSON, TransformOptions{Admission: &AdmissionPolicy{RequireDigest: true}})
+
+	var admissionErr *AdmissionError
+	if !errors.As(err, &admissionErr) {
+		t.Fatalf("Expected AdmissionError, got %v", err)
+	}
+	if failures := admissionErr.Result.Failures(); len(failures) != 1 || failures[0].Rule != "digest-pinned" {
+		t.Errorf("Unexpected failures: %+v", failures)
+	} else if !strings.Contains(failures[0].Message, "pinned to tag 1.0.0") {
+		t.Errorf("Expected the version reported as a tag, got %s", failures[0].Message)
+	}
+}
+
+func TestTransformAdmissionPrivilegedRuntimeArgs(t *testing.T) {
+	registryJSON := `{
+		"server": {
+			"name": "io.github.example/privileged",
+			"description": "Privileged server",
+			"version": "1.0.0",
+			"packages": [
+				{
+					"registryType": "oci",
+					"identifier": "docker.io/example/privileged",
+					"version": "1.0.0",
+					"transport": {"type": "stdio"},
+					"runtimeArguments": [
+						{"type": "named", "name": "--cap-add", "value": "NET_ADMIN"},
+						{"type": "positional", "value": "--privileged"},
+						{"type": "positional", "value": "--device=/dev/fuse"}
+					]
+				}
+			]
+		}
+	}`
+
+	_, err := TransformJSONWithOptions(registryJSON, TransformOptions{Admission: &AdmissionPolicy{ForbidPrivileged: true}})
+
+	var admissionErr *AdmissionError
+	if !errors.As(err, &admissionErr) {
+		t.Fatalf("Expected AdmissionError, got %v", err)
+	}
+	failures := admissionErr.Result.Failures()
+	if len(failures) != 1 || failures[0].Rule != "unprivileged" {
+		t.Fatalf("Unexpected failures: %+v", failures)
+	}
+	for _, flag := range []string{"--cap-add NET_ADMIN", "--privileged", "--device /dev/fuse"} {
+		if !strings.Contains(failures[0].Message, flag) {
+			t.Errorf("Expected %s in %s", flag, failures[0].Message)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package catalogs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	v0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// BatchOptions control TransformBatchJSON
+type BatchOptions struct {
+	Transform TransformOptions
+	// Version, when set, picks one version of each server with the version
+	// resolver instead of relying on isLatest. Without it, servers that have
+	// no official metadata get the resolver's latest version.
+	Version *VersionSelector
+	// Name and DisplayName label the generated catalog
+	Name        string
+	DisplayName string
+}
+
+// BatchSkip is a registry entry that was not converted
+type BatchSkip struct {
+	Server  string `json:"server"`
+	Version string `json:"version"`
+	Reason  string `json:"reason"`
+}
+
+// BatchResult is the outcome of TransformBatchJSON
+type BatchResult struct {
+	// Results are keyed by catalog server name
+	Results map[string]*TransformResult
+	Skipped []BatchSkip
+	// Errors are keyed by registry server name@version, or by name alone
+	// when no version could be selected
+	Errors map[string]error
+	// Warnings are about the batch as a whole, such as non-semver versions
+	Warnings []string
+	// UnusedOverlays name overlays whose server is not in the catalog
+	UnusedOverlays []string
+	// JSON is the catalog in the private-catalog.json format
+	JSON string
+}
+
+// TransformBatchJSON transforms a registry list response (the body of
+// /v0/servers) into a catalog
+func TransformBatchJSON(listJSON string, opts BatchOptions) (*BatchResult, error) {
+	list, err := parseServerListResponse([]byte(listJSON))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse registry list JSON: %w", err)
+	}
+
+	result := &BatchResult{
+		Results: make(map[string]*TransformResult),
+		Errors:  make(map[string]error),
+	}
+	skip := func(name, version, reason string) {
+		result.Skipped = append(result.Skipped, BatchSkip{Server: name, Version: version, Reason: reason})
+	}
+
+	for _, response := range selectVersions(list.Servers, opts, result, skip) {
+		name, version := response.Server.Name, response.Server.Version
+
+		transformed, err := transformResponse(response, opts.Transform)
+		var inactive *InactiveServerError
+		switch {
+		case errors.As(err, &inactive):
+			skip(name, version, fmt.Sprintf("%s in the registry", inactive.Status))
+			continue
+		case err != nil:
+			result.Errors[name+"@"+version] = err
+			continue
+		}
+
+		if existing, ok := result.Results[transformed.Server.Name]; ok {
+			skip(name, version, fmt.Sprintf("%s was already converted from another registry entry", existing.Server.Name))
+			continue
+		}
+		result.Results[transformed.Server.Name] = transformed
+	}
+
+	sort.SliceStable(result.Skipped, func(i, j int) bool {
+		return result.Skipped[i].Server < result.Skipped[j].Server
+	})
+
+	servers := make(map[string]catalog.Server, len(result.Results))
+	registry := make(map[string]catalogServerJSON, len(result.Results))
+	for name, transformed := range result.Results {
+		servers[name] = *transformed.Server
+		registry[name] = newCatalogServerJSON(transformed)
+	}
+	result.UnusedOverlays = UnusedOverlays(opts.Transform.Overlays, servers)
+	catalogJSON, err := json.MarshalIndent(struct {
+		Name        string                       `json:"name,omitempty"`
+		DisplayName string                       `json:"displayName,omitempty"`
+		Registry    map[string]catalogServerJSON `json:"registry"`
+	}{opts.Name, opts.DisplayName, registry}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal catalog JSON: %w", err)
+	}
+	result.JSON = string(catalogJSON)
+
+	return result, nil
+}
+
+// selectVersions picks one registry entry per server to convert: the version
+// chosen by the resolver, or the one the registry marks isLatest. The
+// resolver's latest also picks among the versions of servers that have no
+// official metadata.
+func selectVersions(servers []v0.ServerResponse, opts BatchOptions, result *BatchResult, skip func(name, version, reason string)) []v0.ServerResponse {
+	if opts.Version != nil {
+		return resolveVersions(servers, *opts.Version, result, skip)
+	}
+
+	var selected, unofficial []v0.ServerResponse
+	for _, response := range servers {
+		official := response.Meta.Official
+		switch {
+		case official == nil:
+			unofficial = append(unofficial, response)
+		case !official.IsLatest:
+			skip(response.Server.Name, response.Server.Version, "not the latest version")
+		default:
+			selected = append(selected, response)
+		}
+	}
+	latest, _ := ParseVersionSelector("latest")
+	return append(selected, resolveVersions(unofficial, latest, result, skip)...)
+}
+
+// resolveVersions picks one version of each server with the version resolver
+func resolveVersions(servers []v0.ServerResponse, selector VersionSelector, result *BatchResult, skip func(name, version, reason string)) []v0.ServerResponse {
+	var names []string
+	byName := make(map[string][]v0.ServerResponse)
+	for _, response := range servers {
+		if _, ok := byName[response.Server.Name]; !ok {
+			names = append(names, response.Server.Name)
+		}
+		byName[response.Server.Name] = append(byName[response.Server.Name], response)
+	}
+
+	var selected []v0.ServerResponse
+	for _, name := range names {
+		chosen, warnings, err := ResolveVersion(byName[name], selector)
+		result.Warnings = append(result.Warnings, warnings...)
+		if err != nil {
+			result.Errors[name] = err
+			continue
+		}
+		for _, response := range byName[name] {
+			if response.Server.Version != chosen.Server.Version {
+				skip(name, response.Server.Version, fmt.Sprintf("version %s selected by %s", chosen.Server.Version, selector))
+			}
+		}
+		selected = append(selected, *chosen)
+	}
+	return selected
+}
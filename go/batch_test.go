@@ -0,0 +1,119 @@
+package catalogs
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func listEntry(name, version, status string, isLatest bool) string {
+	return fmt.Sprintf(`{
+		"server": {
+			"name": %q,
+			"description": "Test server",
+			"version": %q,
+			"remotes": [{"type": "streamable-http", "url": "https://%s.example.com/mcp"}]
+		},
+		"_meta": {
+			"io.modelcontextprotocol.registry/official": {
+				"status": %q,
+				"publishedAt": "2025-01-01T00:00:00Z",
+				"isLatest": %t
+			}
+		}
+	}`, name, version, version[:1], status, isLatest)
+}
+
+func TestTransformBatchJSON(t *testing.T) {
+	listJSON := `{"servers": [` +
+		listEntry("com.example/weather", "1.0.0", "active", false) + `,` +
+		listEntry("com.example/weather", "2.0.0", "active", true) + `,` +
+		listEntry("com.example/legacy", "3.0.0", "deleted", true) +
+		`], "metadata": {"count": 3}}`
+
+	opts := BatchOptions{Transform: TransformOptions{Status: StatusSkip}, Name: "test", DisplayName: "Test Catalog"}
+	result, err := TransformBatchJSON(listJSON, opts)
+	if err != nil {
+		t.Fatalf("TransformBatchJSON failed: %v", err)
+	}
+
+	if len(result.Results) != 1 {
+		t.Fatalf("Expected one server, got %d", len(result.Results))
+	}
+	weather := result.Results["com-example-weather"]
+	if weather == nil || weather.Server.Remote.URL != "https://2.example.com/mcp" {
+		t.Errorf("Expected the latest weather version, got %+v", weather)
+	}
+	if len(result.Skipped) != 2 {
+		t.Errorf("Expected old and deleted versions to be skipped, got %+v", result.Skipped)
+	}
+
+	var catalogJSON legacyCatalog
+	if err := json.Unmarshal([]byte(result.JSON), &catalogJSON); err != nil {
+		t.Fatalf("Failed to parse catalog JSON: %v", err)
+	}
+	if catalogJSON.Name != "test" || len(catalogJSON.Registry) != 1 {
+		t.Errorf("Unexpected catalog %+v", catalogJSON)
+	}
+}
+
+func TestTransformBatchJSONVersionSelector(t *testing.T) {
+	listJSON := `{"servers": [` +
+		listEntry("com.example/weather", "1.4.0", "active", false) + `,` +
+		listEntry("com.example/weather", "2.0.0", "active", true) +
+		`]}`
+
+	selector, err := ParseVersionSelector("^1.2")
+	if err != nil {
+		t.Fatalf("ParseVersionSelector failed: %v", err)
+	}
+	result, err := TransformBatchJSON(listJSON, BatchOptions{Version: &selector})
+	if err != nil {
+		t.Fatalf("TransformBatchJSON failed: %v", err)
+	}
+
+	weather := result.Results["com-example-weather"]
+	if weather == nil || weather.Server.Remote.URL != "https://1.example.com/mcp" {
+		t.Errorf("Expected version 1.4.0, got %+v", weather)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0].Version != "2.0.0" {
+		t.Errorf("Expected 2.0.0 to be skipped, got %+v", result.Skipped)
+	}
+}
+
+func TestTransformBatchJSONErrorsPerVersion(t *testing.T) {
+	// The selected version points at a private address, which deny rejects
+	unsafe := strings.Replace(listEntry("com.example/weather", "2.0.0", "active", true),
+		"https://2.example.com/mcp", "http://10.0.0.2/mcp", 1)
+	listJSON := `{"servers": [` + listEntry("com.example/weather", "1.0.0", "active", false) + `,` + unsafe + `]}`
+
+	result, err := TransformBatchJSON(listJSON, BatchOptions{Transform: TransformOptions{RemoteURLs: RemoteURLDeny}})
+	if err != nil {
+		t.Fatalf("TransformBatchJSON failed: %v", err)
+	}
+	if len(result.Errors) != 1 || result.Errors["com.example/weather@2.0.0"] == nil {
+		t.Errorf("Expected an error for com.example/weather@2.0.0, got %v", result.Errors)
+	}
+}
+
+func TestTransformBatchJSONWithoutOfficialMeta(t *testing.T) {
+	entry := func(version string) string {
+		return `{"server": {"name": "com.example/weather", "description": "Test server", "version": "` + version +
+			`", "remotes": [{"type": "streamable-http", "url": "https://` + version[:1] + `.example.com/mcp"}]}}`
+	}
+	// Without isLatest the resolver picks the highest version, not the first
+	listJSON := `{"servers": [` + entry("1.0.0") + `,` + entry("2.0.0") + `]}`
+
+	result, err := TransformBatchJSON(listJSON, BatchOptions{})
+	if err != nil {
+		t.Fatalf("TransformBatchJSON failed: %v", err)
+	}
+	weather := result.Results["com-example-weather"]
+	if weather == nil || weather.Server.Remote.URL != "https://2.example.com/mcp" {
+		t.Errorf("Expected version 2.0.0, got %+v", weather)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0].Version != "1.0.0" {
+		t.Errorf("Expected 1.0.0 to be skipped, got %+v", result.Skipped)
+	}
+}
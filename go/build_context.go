@@ -0,0 +1,333 @@
+package catalogs
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	v0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// BuildContextOptions control GenerateBuildContext
+type BuildContextOptions struct {
+	// ImagePrefix is the repository images are planned under, e.g.
+	// docker.io/acme (default mcp)
+	ImagePrefix string
+	// NodeImage is the base image of npm servers (default node:22-alpine)
+	NodeImage string
+	// PythonImage is the base image of pypi servers; it has to provide uv
+	// (default ghcr.io/astral-sh/uv:python3.12-bookworm-slim)
+	PythonImage string
+}
+
+// BuildContext is the Dockerfile and server.json of an npm or pypi server
+// packaged as an image
+type BuildContext struct {
+	// Name is the catalog server name, used as the context directory
+	Name string
+	// Image is the planned image, repository:version
+	Image      string
+	Dockerfile string
+	// ServerJSON is the registry entry with an OCI package for Image
+	ServerJSON string
+	Warnings   []string
+}
+
+// GenerateBuildContext packages the first npm or pypi package of a server as
+// an image: the Dockerfile installs the exact package version at build time,
+// runs as a non-root user and bakes the runtime and the leading fixed
+// package arguments into the entrypoint. Arguments that need a value at run
+// time stay in the server.json, where they become the catalog command.
+func GenerateBuildContext(server v0.ServerJSON, opts BuildContextOptions) (*BuildContext, error) {
+	if opts.ImagePrefix == "" {
+		opts.ImagePrefix = "mcp"
+	}
+	if opts.NodeImage == "" {
+		opts.NodeImage = "node:22-alpine"
+	}
+	if opts.PythonImage == "" {
+		opts.PythonImage = "ghcr.io/astral-sh/uv:python3.12-bookworm-slim"
+	}
+
+	var pkg *model.Package
+	for i := range server.Packages {
+		switch server.Packages[i].RegistryType {
+		case model.RegistryTypeOCI:
+			return nil, fmt.Errorf("server %s already has an image: %s", server.Name, server.Packages[i].Identifier)
+		case model.RegistryTypeNPM, model.RegistryTypePyPI:
+			if pkg == nil {
+				pkg = &server.Packages[i]
+			}
+		}
+	}
+	if pkg == nil {
+		return nil, fmt.Errorf("server %s has no npm or pypi package", server.Name)
+	}
+	if err := checkExactVersion(*pkg); err != nil {
+		return nil, fmt.Errorf("server %s: %w", server.Name, err)
+	}
+
+	name := extractServerName(server.Name)
+	repository := strings.TrimRight(opts.ImagePrefix, "/") + "/" + strings.ToLower(name)
+	tag := imageTagPattern.ReplaceAllString(pkg.Version, "-")
+	result := &BuildContext{Name: name, Image: repository + ":" + tag}
+	if tag != pkg.Version {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("version %s is not a valid image tag; tagged %s", pkg.Version, tag))
+	}
+
+	runtimeArgs, warnings := fixedRuntimeArgs(*pkg)
+	result.Warnings = append(result.Warnings, warnings...)
+	baked, remaining := splitFixedArgs(*pkg)
+
+	var dockerfile string
+	var err error
+	switch pkg.RegistryType {
+	case model.RegistryTypeNPM:
+		dockerfile, err = npmDockerfile(server, *pkg, opts.NodeImage, runtimeArgs, baked)
+	case model.RegistryTypePyPI:
+		dockerfile, err = pypiDockerfile(server, *pkg, opts.PythonImage, runtimeArgs, baked)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("server %s: %w", server.Name, err)
+	}
+	result.Dockerfile = dockerfile
+
+	// The planned image replaces the packages; the transform writes the
+	// catalog image as repository:tag, the image the Dockerfile is built as
+	planned := server
+	if planned.Schema == "" {
+		planned.Schema = model.CurrentSchemaURL
+	}
+	planned.Packages = []model.Package{{
+		RegistryType:         model.RegistryTypeOCI,
+		Identifier:           repository,
+		Version:              tag,
+		Transport:            pkg.Transport,
+		PackageArguments:     remaining,
+		EnvironmentVariables: pkg.EnvironmentVariables,
+	}}
+	data, err := json.MarshalIndent(v0.ServerResponse{Server: planned}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal server.json: %w", err)
+	}
+	result.ServerJSON = string(data)
+	return result, nil
+}
+
+// BuildContextJSON generates the build context of one registry entry, as
+// returned by /v0/servers/{name}/versions/{version}
+func BuildContextJSON(registryJSON string, opts BuildContextOptions) (*BuildContext, error) {
+	response, err := parseServerResponse([]byte(registryJSON))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse registry JSON: %w", err)
+	}
+	return GenerateBuildContext(response.Server, opts)
+}
+
+// BuildContextsResult is the outcome of BuildContextsJSON
+type BuildContextsResult struct {
+	// Contexts are sorted by name, one per context directory
+	Contexts []*BuildContext
+	Skipped  []BatchSkip
+	// Errors are keyed by source when an entry does not parse, otherwise by
+	// registry server name@version, or by name alone when no version could
+	// be selected
+	Errors map[string]error
+	// Warnings are about the entries as a whole, such as non-semver versions
+	Warnings []string
+}
+
+// BuildContextsJSON generates the build contexts of several registry entries
+// keyed by source, such as the version URLs of npm-urls.txt. A context
+// directory holds one server, so the resolver's latest version of each server
+// is used, and a server whose name maps to the directory of another is
+// skipped.
+func BuildContextsJSON(registryJSONs map[string]string, opts BuildContextOptions) *BuildContextsResult {
+	result := &BuildContextsResult{Errors: make(map[string]error)}
+	skip := func(name, version, reason string) {
+		result.Skipped = append(result.Skipped, BatchSkip{Server: name, Version: version, Reason: reason})
+	}
+
+	var servers []v0.ServerResponse
+	for _, source := range sortedMapKeys(registryJSONs) {
+		response, err := parseServerResponse([]byte(registryJSONs[source]))
+		if err != nil {
+			result.Errors[source] = fmt.Errorf("failed to parse registry JSON: %w", err)
+			continue
+		}
+		servers = append(servers, response)
+	}
+	sort.SliceStable(servers, func(i, j int) bool {
+		return servers[i].Server.Name < servers[j].Server.Name
+	})
+
+	// Errors go through a BatchResult so resolveVersions keys them the same way
+	batch := &BatchResult{Errors: result.Errors}
+	latest, _ := ParseVersionSelector("latest")
+	selected := resolveVersions(servers, latest, batch, skip)
+	result.Warnings = batch.Warnings
+
+	owners := make(map[string]string)
+	for _, response := range selected {
+		name, version := response.Server.Name, response.Server.Version
+		context, err := GenerateBuildContext(response.Server, opts)
+		if err != nil {
+			result.Errors[name+"@"+version] = err
+			continue
+		}
+		if owner, ok := owners[context.Name]; ok {
+			skip(name, version, fmt.Sprintf("directory %s is already used by %s", context.Name, owner))
+			continue
+		}
+		owners[context.Name] = name
+		result.Contexts = append(result.Contexts, context)
+	}
+
+	sort.SliceStable(result.Skipped, func(i, j int) bool {
+		return result.Skipped[i].Server < result.Skipped[j].Server
+	})
+	sort.Slice(result.Contexts, func(i, j int) bool {
+		return result.Contexts[i].Name < result.Contexts[j].Name
+	})
+	return result
+}
+
+// imageTagPattern matches what is not allowed in an image tag, e.g. the + of
+// a local version
+var imageTagPattern = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// checkExactVersion rejects versions a Dockerfile cannot pin
+func checkExactVersion(pkg model.Package) error {
+	if pkg.Version == "" || pkg.Version == "latest" || strings.ContainsAny(pkg.Version, "^~*<>=|, ") || strings.HasSuffix(pkg.Version, ".x") {
+		return fmt.Errorf("package %s version %q is not an exact version", pkg.Identifier, pkg.Version)
+	}
+	return nil
+}
+
+// fixedArg renders an argument whose value is known at build time
+func fixedArg(arg model.Argument) ([]string, bool) {
+	if len(arg.Variables) > 0 || urlVariablePattern.MatchString(arg.Value) {
+		return nil, false
+	}
+	if arg.Type == model.ArgumentTypeNamed {
+		switch {
+		case arg.Value != "":
+			return []string{arg.Name + "=" + arg.Value}, true
+		case arg.Default == "" && arg.ValueHint == "" && !arg.IsRequired:
+			// A flag without a value
+			return []string{arg.Name}, true
+		}
+		return nil, false
+	}
+	if arg.Value == "" {
+		return nil, false
+	}
+	return []string{arg.Value}, true
+}
+
+// fixedRuntimeArgs renders the runtime arguments. The package is installed at
+// build time, so npx's -y is dropped, and arguments that need a value cannot
+// be passed at run time.
+func fixedRuntimeArgs(pkg model.Package) ([]string, []string) {
+	var args, warnings []string
+	for _, arg := range pkg.RuntimeArguments {
+		if arg.Value == "-y" || arg.Value == "--yes" || arg.Name == "-y" || arg.Name == "--yes" {
+			continue
+		}
+		rendered, ok := fixedArg(arg)
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("runtime argument %s needs a value at run time; left out", parseRuntimeArg(arg)))
+			continue
+		}
+		args = append(args, rendered...)
+	}
+	return args, warnings
+}
+
+// splitFixedArgs bakes the leading package arguments with known values into
+// the entrypoint. The rest, from the first argument that needs a value (or
+// names a transport URL variable), stay in order in the server.json.
+func splitFixedArgs(pkg model.Package) (baked []string, remaining []model.Argument) {
+	referenced := make(map[string]bool)
+	for _, match := range urlVariablePattern.FindAllStringSubmatch(pkg.Transport.URL, -1) {
+		referenced[match[1]] = true
+	}
+	for i, arg := range pkg.PackageArguments {
+		rendered, ok := fixedArg(arg)
+		if !ok || referenced[arg.ValueHint] || referenced[strings.TrimLeft(arg.Name, "-")] {
+			return baked, pkg.PackageArguments[i:]
+		}
+		baked = append(baked, rendered...)
+	}
+	return baked, nil
+}
+
+// execForm renders a JSON-array ENTRYPOINT
+func execForm(args []string) string {
+	data, _ := json.Marshal(args)
+	return string(data)
+}
+
+func npmDockerfile(server v0.ServerJSON, pkg model.Package, base string, runtimeArgs, args []string) (string, error) {
+	if pkg.RunTimeHint != "" && pkg.RunTimeHint != "npx" {
+		return "", fmt.Errorf("unsupported npm runtime hint %q (expected npx)", pkg.RunTimeHint)
+	}
+	spec := pkg.Identifier + "@" + pkg.Version
+	install := "npm install --omit=dev --no-fund --no-audit"
+	if pkg.RegistryBaseURL != "" && pkg.RegistryBaseURL != model.RegistryURLNPM {
+		install += " --registry " + pkg.RegistryBaseURL
+	}
+
+	// npx --no runs the locally installed version and never downloads
+	entrypoint := append([]string{"npx", "--no"}, runtimeArgs...)
+	entrypoint = append(entrypoint, spec)
+	entrypoint = append(entrypoint, args...)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s %s, generated from the registry npm package %s\n", server.Name, server.Version, spec)
+	fmt.Fprintf(&b, "FROM %s\n", base)
+	b.WriteString("ENV NODE_ENV=production\n")
+	b.WriteString("WORKDIR /app\n")
+	fmt.Fprintf(&b, "RUN %s %s \\\n  && npm cache clean --force\n", install, spec)
+	b.WriteString("USER node\n")
+	fmt.Fprintf(&b, "ENTRYPOINT %s\n", execForm(entrypoint))
+	return b.String(), nil
+}
+
+// pypiToolBinDir is where uv tool install puts the executables of pypi servers
+const pypiToolBinDir = "/home/mcp/.local/bin"
+
+func pypiDockerfile(server v0.ServerJSON, pkg model.Package, base string, runtimeArgs, args []string) (string, error) {
+	if pkg.RunTimeHint != "" && pkg.RunTimeHint != "uvx" {
+		return "", fmt.Errorf("unsupported pypi runtime hint %q (expected uvx)", pkg.RunTimeHint)
+	}
+	// uvx flags such as --python or --with shape the tool environment, so
+	// they go to the install; the entrypoint runs the installed executable,
+	// which uvx names after the package
+	install := []string{"uv", "tool", "install"}
+	for _, arg := range runtimeArgs {
+		install = append(install, shellQuote(arg))
+	}
+	install = append(install, shellQuote(pkg.Identifier+"=="+pkg.Version))
+	if pkg.RegistryBaseURL != "" && pkg.RegistryBaseURL != model.RegistryURLPyPI {
+		install = append(install, "--index-url", shellQuote(pkg.RegistryBaseURL))
+	}
+
+	entrypoint := append([]string{pypiToolBinDir + "/" + pkg.Identifier}, args...)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s %s, generated from the registry pypi package %s==%s\n", server.Name, server.Version, pkg.Identifier, pkg.Version)
+	fmt.Fprintf(&b, "FROM %s\n", base)
+	b.WriteString("RUN useradd --create-home --uid 1000 mcp\n")
+	b.WriteString("USER mcp\n")
+	b.WriteString("WORKDIR /home/mcp\n")
+	// The uv images point UV_TOOL_BIN_DIR at /usr/local/bin, which the mcp
+	// user can't write; keep the tool in its home
+	fmt.Fprintf(&b, "ENV UV_TOOL_DIR=/home/mcp/.local/share/uv/tools UV_TOOL_BIN_DIR=%s\n", pypiToolBinDir)
+	fmt.Fprintf(&b, "RUN %s\n", strings.Join(install, " "))
+	fmt.Fprintf(&b, "ENTRYPOINT %s\n", execForm(entrypoint))
+	return b.String(), nil
+}
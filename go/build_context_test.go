@@ -0,0 +1,201 @@
+package catalogs
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	v0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+const npmRegistryJSON = `{
+	"server": {
+		"name": "io.github.example/notes",
+		"description": "Notes over MCP",
+		"version": "1.4.2",
+		"packages": [{
+			"registryType": "npm",
+			"identifier": "@example/notes-mcp",
+			"version": "1.4.2",
+			"runtimeHint": "npx",
+			"transport": {"type": "stdio"},
+			"runtimeArguments": [{"type": "positional", "value": "-y"}],
+			"packageArguments": [
+				{"type": "positional", "value": "serve"},
+				{"type": "named", "name": "--stdio"},
+				{"type": "named", "name": "--dir", "valueHint": "directory", "isRequired": true},
+				{"type": "positional", "value": "--verbose"}
+			],
+			"environmentVariables": [{"name": "NOTES_TOKEN", "isSecret": true, "isRequired": true}]
+		}]
+	}
+}`
+
+func TestBuildContextNPM(t *testing.T) {
+	context, err := BuildContextJSON(npmRegistryJSON, BuildContextOptions{ImagePrefix: "docker.io/acme/"})
+	if err != nil {
+		t.Fatalf("BuildContextJSON failed: %v", err)
+	}
+	if context.Name != "io-github-example-notes" || context.Image != "docker.io/acme/io-github-example-notes:1.4.2" {
+		t.Errorf("Unexpected context %s, %s", context.Name, context.Image)
+	}
+	for _, want := range []string{
+		"FROM node:22-alpine\n",
+		"RUN npm install --omit=dev --no-fund --no-audit @example/notes-mcp@1.4.2",
+		"USER node\n",
+		`ENTRYPOINT ["npx","--no","@example/notes-mcp@1.4.2","serve","--stdio"]`,
+	} {
+		if !strings.Contains(context.Dockerfile, want) {
+			t.Errorf("Expected %q in Dockerfile:\n%s", want, context.Dockerfile)
+		}
+	}
+
+	response, err := parseServerResponse([]byte(context.ServerJSON))
+	if err != nil {
+		t.Fatalf("server.json is not a registry entry: %v", err)
+	}
+	if len(response.Server.Packages) != 1 {
+		t.Fatalf("Expected one package, got %+v", response.Server.Packages)
+	}
+	pkg := response.Server.Packages[0]
+	if pkg.RegistryType != model.RegistryTypeOCI || pkg.Identifier != "docker.io/acme/io-github-example-notes" || pkg.Version != "1.4.2" {
+		t.Errorf("Unexpected OCI package %+v", pkg)
+	}
+	var remaining []string
+	for _, arg := range pkg.PackageArguments {
+		remaining = append(remaining, arg.Name+arg.Value)
+	}
+	if !reflect.DeepEqual(remaining, []string{"--dir", "--verbose"}) {
+		t.Errorf("Expected the arguments from --dir on to remain, got %v", remaining)
+	}
+	if response.Server.Schema != model.CurrentSchemaURL || len(pkg.EnvironmentVariables) != 1 {
+		t.Errorf("Unexpected server %+v", response.Server)
+	}
+
+	// The server.json transforms into a server running the planned image
+	result, err := transform(response.Server, nil)
+	if err != nil {
+		t.Fatalf("transform failed: %v", err)
+	}
+	if server := result.Server; server.Image != context.Image || len(server.Secrets) != 1 {
+		t.Errorf("Expected image %s, got catalog server %+v", context.Image, result.Server)
+	}
+}
+
+func TestBuildContextPyPI(t *testing.T) {
+	server := v0.ServerJSON{
+		Name:    "io.github.example/fetch",
+		Version: "0.6.3+local",
+		Packages: []model.Package{{
+			RegistryType:    model.RegistryTypePyPI,
+			RegistryBaseURL: "https://pypi.example.com/simple",
+			Identifier:      "mcp-server-fetch",
+			Version:         "0.6.3+local",
+			RunTimeHint:     "uvx",
+			Transport:       model.Transport{Type: model.TransportTypeStreamableHTTP, URL: "http://localhost:{port}/mcp"},
+			RuntimeArguments: []model.Argument{
+				{Type: model.ArgumentTypeNamed, Name: "--python", ValueHint: "version"},
+				{Type: model.ArgumentTypeNamed, Name: "--with", InputWithVariables: model.InputWithVariables{Input: model.Input{Value: "httpx"}}},
+			},
+			PackageArguments: []model.Argument{{Type: model.ArgumentTypeNamed, Name: "--port", InputWithVariables: model.InputWithVariables{Input: model.Input{Value: "8080"}}}},
+		}},
+	}
+	context, err := GenerateBuildContext(server, BuildContextOptions{})
+	if err != nil {
+		t.Fatalf("GenerateBuildContext failed: %v", err)
+	}
+	if context.Image != "mcp/io-github-example-fetch:0.6.3-local" {
+		t.Errorf("Unexpected image %s", context.Image)
+	}
+	for _, want := range []string{
+		"FROM ghcr.io/astral-sh/uv:python3.12-bookworm-slim\n",
+		"USER mcp\n",
+		"RUN uv tool install --with=httpx mcp-server-fetch==0.6.3+local --index-url https://pypi.example.com/simple\n",
+		"ENV UV_TOOL_DIR=/home/mcp/.local/share/uv/tools UV_TOOL_BIN_DIR=/home/mcp/.local/bin\n",
+		// The installed tool runs, not a uvx environment rebuilt at start
+		`ENTRYPOINT ["/home/mcp/.local/bin/mcp-server-fetch"]`,
+	} {
+		if !strings.Contains(context.Dockerfile, want) {
+			t.Errorf("Expected %q in Dockerfile:\n%s", want, context.Dockerfile)
+		}
+	}
+	// --port names the transport URL variable, so it stays in the server.json
+	if !strings.Contains(context.ServerJSON, `"--port"`) {
+		t.Errorf("Expected --port to remain:\n%s", context.ServerJSON)
+	}
+	if len(context.Warnings) != 2 || !strings.Contains(context.Warnings[1], "--python") {
+		t.Errorf("Unexpected warnings %v", context.Warnings)
+	}
+
+	// The catalog image is the sanitized tag the Dockerfile is built as
+	response, err := parseServerResponse([]byte(context.ServerJSON))
+	if err != nil {
+		t.Fatalf("server.json is not a registry entry: %v", err)
+	}
+	result, err := transform(response.Server, nil)
+	if err != nil {
+		t.Fatalf("transform failed: %v", err)
+	}
+	if result.Server.Image != context.Image {
+		t.Errorf("Expected image %s, got %s", context.Image, result.Server.Image)
+	}
+}
+
+func TestBuildContextsJSON(t *testing.T) {
+	entry := func(name, version string) string {
+		return `{"server": {"name": "` + name + `", "version": "` + version + `", "packages": [{
+			"registryType": "npm", "identifier": "notes-mcp", "version": "` + version + `", "transport": {"type": "stdio"}
+		}]}}`
+	}
+	result := BuildContextsJSON(map[string]string{
+		"https://registry/notes/1.4.2":   entry("io.github.example/notes", "1.4.2"),
+		"https://registry/notes/1.10.0":  entry("io.github.example/notes", "1.10.0"),
+		"https://registry/notes/1.9.1":   entry("io.github.example/notes", "1.9.1"),
+		"https://registry/other/2.0.0":   entry("io.github/example-notes", "2.0.0"),
+		"https://registry/ranged/1.0.0":  entry("io.github.example/ranged", "^1.0.0"),
+		"https://registry/broken/0.1.0":  `{"server":`,
+		"https://registry/fetch/0.6.3":   entry("io.github.example/fetch", "0.6.3"),
+		"https://registry/fetch/0.6.3-2": entry("io.github.example/fetch", "0.6.3"),
+	}, BuildContextOptions{})
+
+	var images []string
+	for _, context := range result.Contexts {
+		images = append(images, context.Image)
+	}
+	if !reflect.DeepEqual(images, []string{"mcp/io-github-example-fetch:0.6.3", "mcp/io-github-example-notes:1.10.0"}) {
+		t.Errorf("Expected one context per server at the latest version, got %v", images)
+	}
+
+	var skipped []string
+	for _, skip := range result.Skipped {
+		skipped = append(skipped, skip.Server+"@"+skip.Version)
+	}
+	if !reflect.DeepEqual(skipped, []string{"io.github.example/notes@1.4.2", "io.github.example/notes@1.9.1", "io.github/example-notes@2.0.0"}) {
+		t.Errorf("Unexpected skips %+v", result.Skipped)
+	}
+	if reason := result.Skipped[2].Reason; !strings.Contains(reason, "directory io-github-example-notes is already used by io.github.example/notes") {
+		t.Errorf("Expected a directory collision, got %q", reason)
+	}
+
+	if len(result.Errors) != 2 || result.Errors["https://registry/broken/0.1.0"] == nil || result.Errors["io.github.example/ranged@^1.0.0"] == nil {
+		t.Errorf("Unexpected errors %v", result.Errors)
+	}
+}
+
+func TestBuildContextErrors(t *testing.T) {
+	pkg := func(registryType, version, hint string) v0.ServerJSON {
+		return v0.ServerJSON{Name: "io.github.example/x", Packages: []model.Package{{RegistryType: registryType, Identifier: "x", Version: version, RunTimeHint: hint}}}
+	}
+	for name, server := range map[string]v0.ServerJSON{
+		"image":        pkg(model.RegistryTypeOCI, "1.0.0", ""),
+		"no package":   {Name: "io.github.example/x"},
+		"range":        pkg(model.RegistryTypeNPM, "^1.0.0", "npx"),
+		"latest":       pkg(model.RegistryTypeNPM, "latest", "npx"),
+		"runtime hint": pkg(model.RegistryTypePyPI, "1.0.0", "pipx"),
+	} {
+		if _, err := GenerateBuildContext(server, BuildContextOptions{}); err == nil {
+			t.Errorf("Expected error for %s", name)
+		}
+	}
+}
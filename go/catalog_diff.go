@@ -0,0 +1,368 @@
+package catalogs
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+// ChangeKind says how a field changed
+type ChangeKind string
+
+const (
+	ChangeAdded   ChangeKind = "added"
+	ChangeRemoved ChangeKind = "removed"
+	ChangeChanged ChangeKind = "changed"
+	// ChangeDigestBump is a new digest for the same image repository
+	ChangeDigestBump ChangeKind = "digest-bump"
+)
+
+// FieldChange is one semantic change to a catalog server. Field names the
+// part that changed, e.g. image, secrets, remote.url or config.region.
+type FieldChange struct {
+	Field string     `json:"field"`
+	Kind  ChangeKind `json:"kind"`
+	Old   string     `json:"old,omitempty"`
+	New   string     `json:"new,omitempty"`
+	// Required is set for secrets and config that must be provided
+	Required bool `json:"required,omitempty"`
+}
+
+func (c FieldChange) String() string {
+	required := ""
+	if c.Required {
+		required = "required "
+	}
+	switch c.Kind {
+	case ChangeAdded:
+		return fmt.Sprintf("%s: added %s%s", c.Field, required, c.New)
+	case ChangeRemoved:
+		return fmt.Sprintf("%s: removed %s%s", c.Field, required, c.Old)
+	case ChangeDigestBump:
+		return fmt.Sprintf("%s: digest bump %s -> %s", c.Field, c.Old, c.New)
+	}
+	return fmt.Sprintf("%s: %s -> %s", c.Field, c.Old, c.New)
+}
+
+// ServerDiff lists the changes to one server
+type ServerDiff struct {
+	Server  string        `json:"server"`
+	Changes []FieldChange `json:"changes"`
+}
+
+// CatalogDiff is the semantic difference between two catalogs
+type CatalogDiff struct {
+	Added   []string     `json:"added,omitempty"`
+	Removed []string     `json:"removed,omitempty"`
+	Changed []ServerDiff `json:"changed,omitempty"`
+}
+
+// Empty reports whether the catalogs are equivalent
+func (d CatalogDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffCatalogs compares two catalogs keyed by server name, as returned by
+// LoadCatalog
+func DiffCatalogs(old, new map[string]catalog.Server) CatalogDiff {
+	var diff CatalogDiff
+	for _, name := range SortedServerNames(old) {
+		if _, ok := new[name]; !ok {
+			diff.Removed = append(diff.Removed, name)
+		}
+	}
+	for _, name := range SortedServerNames(new) {
+		oldServer, ok := old[name]
+		if !ok {
+			diff.Added = append(diff.Added, name)
+			continue
+		}
+		if changes := DiffServers(oldServer, new[name]); len(changes) > 0 {
+			diff.Changed = append(diff.Changed, ServerDiff{Server: name, Changes: changes})
+		}
+	}
+	return diff
+}
+
+// DiffServers returns the field-level changes between two versions of a
+// catalog server
+func DiffServers(old, new catalog.Server) []FieldChange {
+	var changes []FieldChange
+	changes = append(changes, diffImage(old.Image, new.Image)...)
+	changes = append(changes, diffScalar("type", old.Type, new.Type)...)
+	changes = append(changes, diffScalar("remote.url", old.Remote.URL, new.Remote.URL)...)
+	changes = append(changes, diffScalar("remote.transport_type", old.Remote.Transport, new.Remote.Transport)...)
+	changes = append(changes, diffNamed("remote.headers", old.Remote.Headers, new.Remote.Headers, false)...)
+	changes = append(changes, diffScalar("sseEndpoint", old.SSEEndpoint, new.SSEEndpoint)...)
+	changes = append(changes, diffNamed("secrets", secretsByName(old.Secrets), secretsByName(new.Secrets), true)...)
+	changes = append(changes, diffNamed("env", envByName(old.Env), envByName(new.Env), false)...)
+	changes = append(changes, diffConfig(old.Config, new.Config)...)
+	changes = append(changes, diffOAuth(old.OAuth, new.OAuth)...)
+
+	// Everything else is compared as a whole
+	others := []struct {
+		field    string
+		old, new any
+	}{
+		{"title", old.Title, new.Title},
+		{"description", old.Description, new.Description},
+		{"icon", old.Icon, new.Icon},
+		{"readme", old.ReadmeURL, new.ReadmeURL},
+		{"longLived", old.LongLived, new.LongLived},
+		{"command", old.Command, new.Command},
+		{"volumes", old.Volumes, new.Volumes},
+		{"user", old.User, new.User},
+		{"disableNetwork", old.DisableNetwork, new.DisableNetwork},
+		{"allowHosts", old.AllowHosts, new.AllowHosts},
+		{"extraHosts", old.ExtraHosts, new.ExtraHosts},
+		{"tools", old.Tools, new.Tools},
+		{"prefix", old.Prefix, new.Prefix},
+		{"metadata", old.Metadata, new.Metadata},
+	}
+	for _, other := range others {
+		changes = append(changes, diffScalar(other.field, compactJSON(other.old), compactJSON(other.new))...)
+	}
+	return changes
+}
+
+// compactJSON renders a field for display, with zero values as ""
+func compactJSON(v any) string {
+	if v == nil || reflect.ValueOf(v).IsZero() {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+func diffScalar(field, old, new string) []FieldChange {
+	switch {
+	case old == new:
+		return nil
+	case old == "":
+		return []FieldChange{{Field: field, Kind: ChangeAdded, New: new}}
+	case new == "":
+		return []FieldChange{{Field: field, Kind: ChangeRemoved, Old: old}}
+	}
+	return []FieldChange{{Field: field, Kind: ChangeChanged, Old: old, New: new}}
+}
+
+func diffImage(old, new string) []FieldChange {
+	if old == "" || new == "" || old == new {
+		return diffScalar("image", old, new)
+	}
+	// Only a sha256 digest on both sides is a digest bump; a catalog image's
+	// @version is a tag
+	oldRef, newRef := parseImageReference(old), parseImageReference(new)
+	if oldRef.Registry == newRef.Registry && oldRef.Repository == newRef.Repository && oldRef.Tag == newRef.Tag &&
+		strings.HasPrefix(oldRef.Digest, "sha256:") && strings.HasPrefix(newRef.Digest, "sha256:") {
+		return []FieldChange{{Field: "image", Kind: ChangeDigestBump, Old: oldRef.Digest, New: newRef.Digest}}
+	}
+	return diffScalar("image", old, new)
+}
+
+func secretsByName(secrets []catalog.Secret) map[string]string {
+	byName := make(map[string]string, len(secrets))
+	for _, secret := range secrets {
+		byName[secret.Name] = secret.Env
+	}
+	return byName
+}
+
+func envByName(env []catalog.Env) map[string]string {
+	byName := make(map[string]string, len(env))
+	for _, e := range env {
+		byName[e.Name] = e.Value
+	}
+	return byName
+}
+
+// diffNamed compares name -> value maps, reporting each entry under
+// field.name. Added and removed entries are described as "name=value".
+func diffNamed(field string, old, new map[string]string, required bool) []FieldChange {
+	describe := func(name, value string) string {
+		if value == "" {
+			return name
+		}
+		return fmt.Sprintf("%s (%s)", name, value)
+	}
+
+	var changes []FieldChange
+	for _, name := range sortedMapKeys(old) {
+		if _, ok := new[name]; !ok {
+			changes = append(changes, FieldChange{Field: field, Kind: ChangeRemoved, Old: describe(name, old[name]), Required: required})
+		}
+	}
+	for _, name := range sortedMapKeys(new) {
+		oldValue, ok := old[name]
+		switch {
+		case !ok:
+			changes = append(changes, FieldChange{Field: field, Kind: ChangeAdded, New: describe(name, new[name]), Required: required})
+		case oldValue != new[name]:
+			changes = append(changes, FieldChange{Field: field + "." + name, Kind: ChangeChanged, Old: oldValue, New: new[name]})
+		}
+	}
+	return changes
+}
+
+func sortedMapKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// configProperty is one property of a server's config schema
+type configProperty struct {
+	Schema   string
+	Required bool
+}
+
+// configProperties flattens the config schemas of a server
+func configProperties(config []any) map[string]configProperty {
+	properties := make(map[string]configProperty)
+	for _, item := range config {
+		schema, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		required := make(map[string]bool)
+		if list, ok := schema["required"].([]any); ok {
+			for _, name := range list {
+				required[fmt.Sprint(name)] = true
+			}
+		}
+		if list, ok := schema["required"].([]string); ok {
+			for _, name := range list {
+				required[name] = true
+			}
+		}
+		props, _ := schema["properties"].(map[string]any)
+		for name, prop := range props {
+			properties[name] = configProperty{Schema: compactJSON(prop), Required: required[name]}
+		}
+	}
+	return properties
+}
+
+func diffConfig(old, new []any) []FieldChange {
+	oldProps, newProps := configProperties(old), configProperties(new)
+	var changes []FieldChange
+	for _, name := range sortedMapKeys(oldProps) {
+		if _, ok := newProps[name]; !ok {
+			changes = append(changes, FieldChange{Field: "config", Kind: ChangeRemoved, Old: name, Required: oldProps[name].Required})
+		}
+	}
+	for _, name := range sortedMapKeys(newProps) {
+		oldProp, ok := oldProps[name]
+		newProp := newProps[name]
+		switch {
+		case !ok:
+			changes = append(changes, FieldChange{Field: "config", Kind: ChangeAdded, New: name, Required: newProp.Required})
+		case oldProp.Required != newProp.Required:
+			changes = append(changes, FieldChange{Field: "config." + name + ".required", Kind: ChangeChanged,
+				Old: fmt.Sprint(oldProp.Required), New: fmt.Sprint(newProp.Required), Required: newProp.Required})
+		case oldProp.Schema != newProp.Schema:
+			changes = append(changes, FieldChange{Field: "config." + name, Kind: ChangeChanged, Old: oldProp.Schema, New: newProp.Schema})
+		}
+	}
+	return changes
+}
+
+func diffOAuth(old, new *catalog.OAuth) []FieldChange {
+	providers := func(oauth *catalog.OAuth) map[string]string {
+		byName := make(map[string]string)
+		if oauth != nil {
+			for _, provider := range oauth.Providers {
+				byName[provider.Provider] = strings.TrimSpace(provider.Secret + " " + provider.Env)
+			}
+		}
+		return byName
+	}
+	scopes := func(oauth *catalog.OAuth) string {
+		if oauth == nil {
+			return ""
+		}
+		sorted := append([]string{}, oauth.Scopes...)
+		sort.Strings(sorted)
+		return strings.Join(sorted, " ")
+	}
+
+	changes := diffNamed("oauth.providers", providers(old), providers(new), false)
+	return append(changes, diffScalar("oauth.scopes", scopes(old), scopes(new))...)
+}
+
+// Text renders the diff for a terminal
+func (d CatalogDiff) Text() string {
+	var b strings.Builder
+	for _, name := range d.Added {
+		fmt.Fprintf(&b, "+ %s\n", name)
+	}
+	for _, name := range d.Removed {
+		fmt.Fprintf(&b, "- %s\n", name)
+	}
+	for _, server := range d.Changed {
+		fmt.Fprintf(&b, "~ %s\n", server.Server)
+		for _, change := range server.Changes {
+			fmt.Fprintf(&b, "    %s\n", change)
+		}
+	}
+	return b.String()
+}
+
+// Markdown renders the diff for a pull request description
+func (d CatalogDiff) Markdown() string {
+	var b strings.Builder
+	b.WriteString("## Catalog changes\n\n")
+	if d.Empty() {
+		b.WriteString("No changes.\n")
+		return b.String()
+	}
+	if len(d.Added) > 0 {
+		b.WriteString("### Added\n\n")
+		for _, name := range d.Added {
+			fmt.Fprintf(&b, "- `%s`\n", name)
+		}
+		b.WriteString("\n")
+	}
+	if len(d.Removed) > 0 {
+		b.WriteString("### Removed\n\n")
+		for _, name := range d.Removed {
+			fmt.Fprintf(&b, "- `%s`\n", name)
+		}
+		b.WriteString("\n")
+	}
+	if len(d.Changed) > 0 {
+		b.WriteString("### Changed\n\n")
+		for _, server := range d.Changed {
+			fmt.Fprintf(&b, "#### `%s`\n\n| Field | Change | Old | New |\n| --- | --- | --- | --- |\n", server.Server)
+			for _, change := range server.Changes {
+				kind := string(change.Kind)
+				if change.Required {
+					kind += " (required)"
+				}
+				fmt.Fprintf(&b, "| `%s` | %s | %s | %s |\n", change.Field, kind, markdownCell(change.Old), markdownCell(change.New))
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+func markdownCell(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "`" + strings.ReplaceAll(s, "|", `\|`) + "`"
+}
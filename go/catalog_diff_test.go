@@ -0,0 +1,133 @@
+package catalogs
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+func changesByField(changes []FieldChange) map[string]FieldChange {
+	byField := make(map[string]FieldChange)
+	for _, change := range changes {
+		byField[change.Field+":"+string(change.Kind)] = change
+	}
+	return byField
+}
+
+func TestDiffServers(t *testing.T) {
+	old := catalog.Server{
+		Name:    "com-example-weather",
+		Type:    "server",
+		Image:   "docker.io/example/weather@sha256:1111111111111111111111111111111111111111111111111111111111111111",
+		Title:   "Weather",
+		Secrets: []catalog.Secret{{Name: "com-example-weather.api_key", Env: "API_KEY"}},
+		Env:     []catalog.Env{{Name: "UNITS", Value: "metric"}, {Name: "DEBUG", Value: "false"}},
+		Config: []any{map[string]any{
+			"name":       "com-example-weather",
+			"properties": map[string]any{"city": map[string]any{"type": "string"}},
+		}},
+	}
+	new := old
+	new.Image = "docker.io/example/weather@sha256:2222222222222222222222222222222222222222222222222222222222222222"
+	new.Title = "Weather Forecasts"
+	new.Secrets = []catalog.Secret{{Name: "com-example-weather.api_key", Env: "API_KEY"}, {Name: "com-example-weather.token", Env: "TOKEN"}}
+	new.Env = []catalog.Env{{Name: "UNITS", Value: "imperial"}}
+	new.Config = []any{map[string]any{
+		"name":       "com-example-weather",
+		"properties": map[string]any{"city": map[string]any{"type": "string"}, "region": map[string]any{"type": "string"}},
+		"required":   []any{"region"},
+	}}
+	new.OAuth = &catalog.OAuth{Providers: []catalog.OAuthProvider{{Provider: "example", Secret: "example.access_token", Env: "ACCESS_TOKEN"}}}
+
+	changes := changesByField(DiffServers(old, new))
+
+	expected := []string{
+		"image:digest-bump",
+		"title:changed",
+		"secrets:added",
+		"env:removed",
+		"env.UNITS:changed",
+		"config:added",
+		"oauth.providers:added",
+	}
+	for _, key := range expected {
+		if _, ok := changes[key]; !ok {
+			t.Errorf("Expected change %s in %v", key, changes)
+		}
+	}
+	if len(changes) != len(expected) {
+		t.Errorf("Expected %d changes, got %v", len(expected), changes)
+	}
+	if secret := changes["secrets:added"]; !secret.Required || secret.New != "com-example-weather.token (TOKEN)" {
+		t.Errorf("Unexpected secret change %+v", secret)
+	}
+	if config := changes["config:added"]; !config.Required || config.New != "region" {
+		t.Errorf("Unexpected config change %+v", config)
+	}
+}
+
+func TestDiffImage(t *testing.T) {
+	digest := func(c string) string { return "sha256:" + strings.Repeat(c, 64) }
+	tests := []struct {
+		old, new string
+		kind     ChangeKind
+	}{
+		{"docker.io/example/weather@" + digest("1"), "docker.io/example/weather@" + digest("2"), ChangeDigestBump},
+		{"docker.io/example/weather@1.0.0", "docker.io/example/weather@1.1.0", ChangeChanged},
+		{"docker.io/example/weather@1.0.0", "docker.io/example/weather@" + digest("2"), ChangeChanged},
+		{"docker.io/example/weather:1.0@" + digest("1"), "docker.io/example/weather:1.1@" + digest("2"), ChangeChanged},
+	}
+
+	for _, tt := range tests {
+		changes := diffImage(tt.old, tt.new)
+		if len(changes) != 1 || changes[0].Kind != tt.kind {
+			t.Errorf("diffImage(%s, %s) = %+v, want %s", tt.old, tt.new, changes, tt.kind)
+		}
+	}
+}
+
+func TestDiffServersRemote(t *testing.T) {
+	old := catalog.Server{Name: "com-example-remote", Type: "remote", Remote: catalog.Remote{URL: "https://example.com/sse", Transport: "sse"}}
+	new := catalog.Server{Name: "com-example-remote", Type: "remote", Remote: catalog.Remote{URL: "https://example.com/mcp", Transport: "streamable-http"}}
+
+	changes := changesByField(DiffServers(old, new))
+	if change := changes["remote.url:changed"]; change.Old != "https://example.com/sse" || change.New != "https://example.com/mcp" {
+		t.Errorf("Expected remote URL change, got %v", changes)
+	}
+	if _, ok := changes["remote.transport_type:changed"]; !ok {
+		t.Errorf("Expected transport change, got %v", changes)
+	}
+}
+
+func TestDiffCatalogs(t *testing.T) {
+	old, err := LoadCatalog("../private-catalog.json")
+	if err != nil {
+		t.Fatalf("LoadCatalog failed: %v", err)
+	}
+	new, err := LoadCatalog("../catalog")
+	if err != nil {
+		t.Fatalf("LoadCatalog failed: %v", err)
+	}
+
+	diff := DiffCatalogs(old, new)
+	if len(diff.Added) == 0 || len(diff.Removed) == 0 {
+		t.Errorf("Expected added and removed servers, got %+v", diff)
+	}
+	if DiffCatalogs(old, old).Empty() != true {
+		t.Error("Expected a catalog to equal itself")
+	}
+
+	text := diff.Text()
+	if !strings.Contains(text, "- com-docker-knowledge-graph") {
+		t.Errorf("Unexpected text output:\n%s", text)
+	}
+	markdown := diff.Markdown()
+	if !strings.Contains(markdown, "### Removed") || !strings.Contains(markdown, "`com-docker-knowledge-graph`") {
+		t.Errorf("Unexpected markdown output:\n%s", markdown)
+	}
+	if _, err := json.Marshal(diff); err != nil {
+		t.Errorf("Failed to marshal diff: %v", err)
+	}
+}
@@ -0,0 +1,366 @@
+package catalogs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	v0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"gopkg.in/yaml.v3"
+)
+
+// legacyCatalog is the top-level shape of private-catalog.json.
+type legacyCatalog struct {
+	Name        string                    `json:"name,omitempty" yaml:"name,omitempty"`
+	DisplayName string                    `json:"displayName,omitempty" yaml:"displayName,omitempty"`
+	Registry    map[string]catalog.Server `json:"registry" yaml:"registry"`
+}
+
+// catalogEntry is the shape of catalog/<server>/server.yaml.
+type catalogEntry struct {
+	Name   string                  `yaml:"name"`
+	Image  string                  `yaml:"image,omitempty"`
+	Type   string                  `yaml:"type"`
+	Remote catalog.Remote          `yaml:"remote,omitempty"`
+	OAuth  []catalog.OAuthProvider `yaml:"oauth,omitempty"`
+	Icon   string                  `yaml:"icon,omitempty"`
+	About  struct {
+		Title       string `yaml:"title,omitempty"`
+		Description string `yaml:"description,omitempty"`
+		Icon        string `yaml:"icon,omitempty"`
+	} `yaml:"about,omitempty"`
+	Run struct {
+		Command        []string          `yaml:"command,omitempty"`
+		Volumes        []string          `yaml:"volumes,omitempty"`
+		Env            map[string]string `yaml:"env,omitempty"`
+		User           string            `yaml:"user,omitempty"`
+		DisableNetwork bool              `yaml:"disableNetwork,omitempty"`
+		AllowHosts     []string          `yaml:"allowHosts,omitempty"`
+	} `yaml:"run,omitempty"`
+	Config struct {
+		Secrets    []entrySecret  `yaml:"secrets,omitempty"`
+		Env        []catalog.Env  `yaml:"env,omitempty"`
+		Parameters map[string]any `yaml:"parameters,omitempty"`
+	} `yaml:"config,omitempty"`
+	Dynamic struct {
+		Tools bool `yaml:"tools,omitempty"`
+	} `yaml:"dynamic,omitempty"`
+}
+
+// entrySecret is a catalog.Secret with the example value catalog files show
+// to users; catalog.Server has no field for it
+type entrySecret struct {
+	Name    string `yaml:"name"`
+	Env     string `yaml:"env"`
+	Example string `yaml:"example,omitempty"`
+}
+
+// EntryMetadata is the metadata of a generated catalog server: the gateway's
+// fields plus what the transform knows that catalog.Server has no field for.
+// The gateway ignores the extra keys.
+type EntryMetadata struct {
+	catalog.Metadata `yaml:",inline"`
+	// AlternateRemotes are the published remotes that were not selected
+	AlternateRemotes []catalog.Remote `json:"alternateRemotes,omitempty" yaml:"alternateRemotes,omitempty"`
+	// ContainerTransport is where an image serving streamable-http or sse
+	// listens; the gateway itself only talks stdio to images
+	ContainerTransport *ContainerTransport `json:"containerTransport,omitempty" yaml:"containerTransport,omitempty"`
+	// Official is the registry's status, publish dates and isLatest flag
+	Official *v0.RegistryExtensions `json:"official,omitempty" yaml:"official,omitempty"`
+}
+
+// catalogServerJSON is a catalog.Server whose metadata carries EntryMetadata
+type catalogServerJSON struct {
+	catalog.Server
+	Metadata *EntryMetadata `json:"metadata,omitempty"`
+}
+
+// newCatalogServerJSON adds what the transform found to the server's metadata
+func newCatalogServerJSON(result *TransformResult) catalogServerJSON {
+	server := catalogServerJSON{Server: *result.Server}
+	var metadata EntryMetadata
+	if result.Server.Metadata != nil {
+		metadata.Metadata = *result.Server.Metadata
+	}
+	metadata.AlternateRemotes = result.AlternateRemotes
+	metadata.ContainerTransport = result.ContainerTransport
+	metadata.Official = result.Official
+	if !reflect.DeepEqual(metadata, EntryMetadata{}) {
+		server.Metadata = &metadata
+	}
+	return server
+}
+
+func (e catalogEntry) toServer() catalog.Server {
+	server := catalog.Server{
+		Name:           e.Name,
+		Type:           e.Type,
+		Image:          e.Image,
+		Title:          e.About.Title,
+		Description:    e.About.Description,
+		Icon:           e.Icon,
+		Remote:         e.Remote,
+		Command:        e.Run.Command,
+		Volumes:        e.Run.Volumes,
+		User:           e.Run.User,
+		DisableNetwork: e.Run.DisableNetwork,
+		AllowHosts:     e.Run.AllowHosts,
+	}
+	if server.Icon == "" {
+		server.Icon = e.About.Icon
+	}
+	for _, secret := range e.Config.Secrets {
+		server.Secrets = append(server.Secrets, catalog.Secret{Name: secret.Name, Env: secret.Env})
+	}
+	if len(e.OAuth) > 0 {
+		server.OAuth = &catalog.OAuth{Providers: e.OAuth}
+	}
+
+	// run.env holds fixed values, config.env holds user-provided ones
+	var envNames []string
+	for name := range e.Run.Env {
+		envNames = append(envNames, name)
+	}
+	sort.Strings(envNames)
+	for _, name := range envNames {
+		server.Env = append(server.Env, catalog.Env{Name: name, Value: e.Run.Env[name]})
+	}
+	server.Env = append(server.Env, e.Config.Env...)
+
+	if len(e.Config.Parameters) > 0 {
+		parameters := map[string]any{"name": e.Name}
+		for k, v := range e.Config.Parameters {
+			parameters[k] = v
+		}
+		server.Config = []any{parameters}
+	}
+
+	return server
+}
+
+// newCatalogEntry is the inverse of toServer. Env values that interpolate
+// config go to config.env, fixed values to run.env.
+func newCatalogEntry(server catalog.Server) catalogEntry {
+	entry := catalogEntry{
+		Name:   server.Name,
+		Image:  server.Image,
+		Type:   server.Type,
+		Remote: server.Remote,
+		Icon:   server.Icon,
+	}
+	entry.About.Title = server.Title
+	entry.About.Description = server.Description
+	entry.About.Icon = server.Icon
+	if server.OAuth != nil {
+		entry.OAuth = server.OAuth.Providers
+	}
+
+	entry.Run.Command = server.Command
+	entry.Run.Volumes = server.Volumes
+	entry.Run.User = server.User
+	entry.Run.DisableNetwork = server.DisableNetwork
+	entry.Run.AllowHosts = server.AllowHosts
+	for _, env := range server.Env {
+		if placeholderPattern.MatchString(env.Value) {
+			entry.Config.Env = append(entry.Config.Env, env)
+			continue
+		}
+		if entry.Run.Env == nil {
+			entry.Run.Env = make(map[string]string)
+		}
+		entry.Run.Env[env.Name] = env.Value
+	}
+
+	for _, secret := range server.Secrets {
+		entry.Config.Secrets = append(entry.Config.Secrets, entrySecret{Name: secret.Name, Env: secret.Env})
+	}
+	if len(server.Config) > 0 {
+		if parameters, ok := server.Config[0].(map[string]any); ok {
+			entry.Config.Parameters = make(map[string]any, len(parameters))
+			for k, v := range parameters {
+				if k != "name" {
+					entry.Config.Parameters[k] = v
+				}
+			}
+		}
+	}
+	return entry
+}
+
+// MarshalCatalogEntry renders a catalog server in the shape of
+// catalog/<server>/server.yaml
+func MarshalCatalogEntry(server catalog.Server) ([]byte, error) {
+	return marshalYAML(newCatalogEntry(server))
+}
+
+// marshalYAML renders YAML indented like the catalog's server.yaml files
+func marshalYAML(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// LoadCatalog reads catalog servers keyed by name from a legacy catalog file
+// (private-catalog.json), a single catalog server file, or a catalog/
+// directory holding one <server>/server.yaml per entry.
+func LoadCatalog(path string) (map[string]catalog.Server, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return loadCatalogDir(path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	servers, err := ParseCatalog(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse catalog %s: %w", path, err)
+	}
+	return servers, nil
+}
+
+// ParseCatalog parses a legacy catalog document or a single catalog server,
+// in either JSON or YAML.
+func ParseCatalog(data []byte) (map[string]catalog.Server, error) {
+	var probe map[string]any
+	if err := yaml.Unmarshal(data, &probe); err != nil {
+		return nil, err
+	}
+
+	if _, ok := probe["registry"]; ok {
+		var legacy legacyCatalog
+		if err := unmarshalJSONOrYAML(data, &legacy); err != nil {
+			return nil, err
+		}
+		servers := make(map[string]catalog.Server, len(legacy.Registry))
+		for name, server := range legacy.Registry {
+			if server.Name == "" {
+				server.Name = name
+			}
+			servers[name] = server
+		}
+		return servers, nil
+	}
+
+	if _, ok := probe["about"]; ok {
+		var entry catalogEntry
+		if err := yaml.Unmarshal(data, &entry); err != nil {
+			return nil, err
+		}
+		return map[string]catalog.Server{entry.Name: entry.toServer()}, nil
+	}
+
+	var server catalog.Server
+	if err := unmarshalJSONOrYAML(data, &server); err != nil {
+		return nil, err
+	}
+	if server.Name == "" {
+		return nil, fmt.Errorf("catalog server has no name")
+	}
+	return map[string]catalog.Server{server.Name: server}, nil
+}
+
+// LoadCatalogMetadata reads the metadata of the servers in a legacy catalog
+// file or a single catalog server file, keyed by server name. catalog/
+// directories hold no generated metadata and give an empty map.
+func LoadCatalogMetadata(path string) (map[string]EntryMetadata, error) {
+	metadata := make(map[string]EntryMetadata)
+	if info, err := os.Stat(path); err != nil {
+		return nil, err
+	} else if info.IsDir() {
+		return metadata, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	type server struct {
+		Name     string         `json:"name" yaml:"name"`
+		Metadata *EntryMetadata `json:"metadata" yaml:"metadata"`
+	}
+	var document struct {
+		server   `yaml:",inline"`
+		Registry map[string]server `json:"registry" yaml:"registry"`
+	}
+	if err := unmarshalJSONOrYAML(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse catalog %s: %w", path, err)
+	}
+	if document.Registry == nil {
+		document.Registry = map[string]server{document.Name: document.server}
+	}
+	for name, server := range document.Registry {
+		if server.Name != "" {
+			name = server.Name
+		}
+		if server.Metadata != nil {
+			metadata[name] = *server.Metadata
+		}
+	}
+	return metadata, nil
+}
+
+func loadCatalogDir(dir string) (map[string]catalog.Server, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	servers := make(map[string]catalog.Server)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		file := filepath.Join(dir, entry.Name(), "server.yaml")
+		data, err := os.ReadFile(file)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		parsed, err := ParseCatalog(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		for name, server := range parsed {
+			servers[name] = server
+		}
+	}
+	return servers, nil
+}
+
+// unmarshalJSONOrYAML decodes JSON documents with encoding/json and
+// anything else as YAML.
+func unmarshalJSONOrYAML(data []byte, v any) error {
+	if json.Valid(data) {
+		return json.Unmarshal(data, v)
+	}
+	return yaml.Unmarshal(data, v)
+}
+
+// SortedServerNames returns the names of servers in a stable order.
+func SortedServerNames(servers map[string]catalog.Server) []string {
+	names := make([]string, 0, len(servers))
+	for name := range servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,113 @@
+package catalogs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadCatalogLegacy(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "private-catalog.json")
+	legacyJSON := `{
+		"name": "private-catalog",
+		"displayName": "Private Catalog",
+		"registry": {
+			"com-google-cloud-bigquery-mcp": {
+				"name": "com-google-cloud-bigquery-mcp",
+				"title": "BigQuery MCP Server",
+				"remote": {
+					"url": "https://bigquery.googleapis.com/mcp",
+					"transport_type": "streamable-http",
+					"headers": {"x-goog-user-project": "${PROJECT_ID}"}
+				},
+				"type": "remote",
+				"oauth": {"providers": [{"provider": "google", "secret": "google.access_token", "env": "ACCESS_TOKEN"}]}
+			},
+			"com-docker-knowledge-graph": {
+				"title": "Knowledge Graph",
+				"type": "server",
+				"image": "docker/knowledge-graph@sha256:abc"
+			}
+		}
+	}`
+	if err := os.WriteFile(file, []byte(legacyJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	servers, err := LoadCatalog(file)
+	if err != nil {
+		t.Fatalf("LoadCatalog failed: %v", err)
+	}
+	if len(servers) != 2 {
+		t.Fatalf("Expected 2 servers, got %d", len(servers))
+	}
+
+	bigquery := servers["com-google-cloud-bigquery-mcp"]
+	if bigquery.Remote.Transport != "streamable-http" {
+		t.Errorf("Expected transport 'streamable-http', got '%s'", bigquery.Remote.Transport)
+	}
+	if !bigquery.IsRemoteOAuthServer() {
+		t.Error("Expected bigquery to be a remote OAuth server")
+	}
+
+	// Registry keys fill in missing names
+	if servers["com-docker-knowledge-graph"].Name != "com-docker-knowledge-graph" {
+		t.Errorf("Expected name from registry key, got '%s'", servers["com-docker-knowledge-graph"].Name)
+	}
+}
+
+func TestLoadCatalogDir(t *testing.T) {
+	dir := t.TempDir()
+	serverYAML := `name: com-google-maps-grounding-lite
+remote:
+  url: https://mapstools.googleapis.com/mcp
+  transport_type: streamable-http
+  headers:
+    X-Goog-Api-Key: ${API_KEY}
+type: remote
+oauth:
+- provider: google
+  secret: google.access_token
+  env: ACCESS_TOKEN
+about:
+  title: Google Maps AI Grounding Lite
+  description: Google Maps data
+  icon: https://www.gstatic.com/images/branding/product/2x/maps_48dp.png
+config:
+  secrets:
+  - name: com-google-maps-grounding-lite.api_key
+    env: API_KEY
+    example: AIzaSyD...
+`
+	if err := os.MkdirAll(filepath.Join(dir, "com-google-maps-grounding-lite"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "com-google-maps-grounding-lite", "server.yaml"), []byte(serverYAML), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	servers, err := LoadCatalog(dir)
+	if err != nil {
+		t.Fatalf("LoadCatalog failed: %v", err)
+	}
+
+	server, ok := servers["com-google-maps-grounding-lite"]
+	if !ok {
+		t.Fatal("Expected com-google-maps-grounding-lite in catalog")
+	}
+	if server.Title != "Google Maps AI Grounding Lite" {
+		t.Errorf("Expected title from about, got '%s'", server.Title)
+	}
+	if server.Icon != "https://www.gstatic.com/images/branding/product/2x/maps_48dp.png" {
+		t.Errorf("Expected icon from about, got '%s'", server.Icon)
+	}
+	if server.Remote.Headers["X-Goog-Api-Key"] != "${API_KEY}" {
+		t.Errorf("Unexpected headers: %v", server.Remote.Headers)
+	}
+	if len(server.Secrets) != 1 || server.Secrets[0].Env != "API_KEY" {
+		t.Errorf("Unexpected secrets: %+v", server.Secrets)
+	}
+	if !server.IsOAuthServer() || server.OAuth.Providers[0].Provider != "google" {
+		t.Errorf("Unexpected oauth: %+v", server.OAuth)
+	}
+}
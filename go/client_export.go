@@ -0,0 +1,284 @@
+package catalogs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+// ClientFormat is an MCP client configuration format
+type ClientFormat string
+
+const (
+	// ClientClaudeDesktop is claude_desktop_config.json. It has no input
+	// prompts, so secrets and config are left as <NAME> placeholders and
+	// remotes go through the mcp-remote bridge.
+	ClientClaudeDesktop ClientFormat = "claude-desktop"
+	// ClientVSCode is .vscode/mcp.json; secrets and config become inputs
+	ClientVSCode ClientFormat = "vscode"
+	// ClientCursor is .cursor/mcp.json; secrets and config are read from
+	// the environment
+	ClientCursor ClientFormat = "cursor"
+)
+
+// ParseClientFormat parses a -format flag value
+func ParseClientFormat(s string) (ClientFormat, error) {
+	switch format := ClientFormat(s); format {
+	case ClientClaudeDesktop, ClientVSCode, ClientCursor:
+		return format, nil
+	}
+	return "", fmt.Errorf("invalid client format %q (expected claude-desktop, vscode or cursor)", s)
+}
+
+// ExportInput is a secret or config value the user has to provide
+type ExportInput struct {
+	// ID is the secret name or the qualified config key (server.key)
+	ID          string `json:"id"`
+	Env         string `json:"env"`
+	Description string `json:"description,omitempty"`
+	Secret      bool   `json:"secret"`
+}
+
+// ExportResult is an MCP client configuration
+type ExportResult struct {
+	JSON     string
+	Inputs   []ExportInput
+	Warnings []string
+}
+
+type clientServer struct {
+	Type    string            `json:"type,omitempty"`
+	Command string            `json:"command,omitempty"`
+	Args    []string          `json:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+	URL     string            `json:"url,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+type vscodeInput struct {
+	Type        string `json:"type"`
+	ID          string `json:"id"`
+	Description string `json:"description,omitempty"`
+	Password    bool   `json:"password,omitempty"`
+}
+
+// clientExporter converts servers for one format, collecting the inputs
+// their secrets and config refer to
+type clientExporter struct {
+	format   ClientFormat
+	inputs   []ExportInput
+	seen     map[string]bool
+	warnings []string
+}
+
+// ExportClientConfig converts catalog servers to an MCP client configuration.
+// Image servers run with docker run over stdio, remotes connect to their URL,
+// and secrets are never written as values.
+func ExportClientConfig(format ClientFormat, servers []catalog.Server) (*ExportResult, error) {
+	e := &clientExporter{format: format, seen: make(map[string]bool)}
+
+	entries := make(map[string]clientServer)
+	for _, server := range servers {
+		var entry clientServer
+		switch {
+		case server.Remote.URL != "":
+			entry = e.remote(server)
+		case server.Image != "":
+			entry = e.image(server)
+		default:
+			e.warnings = append(e.warnings, fmt.Sprintf("%s has neither an image nor a remote, skipped", server.Name))
+			continue
+		}
+		entries[server.Name] = entry
+	}
+
+	var doc any
+	switch format {
+	case ClientVSCode:
+		inputs := []vscodeInput{}
+		for _, input := range e.inputs {
+			inputs = append(inputs, vscodeInput{Type: "promptString", ID: input.ID, Description: input.Description, Password: input.Secret})
+		}
+		doc = struct {
+			Inputs  []vscodeInput           `json:"inputs"`
+			Servers map[string]clientServer `json:"servers"`
+		}{inputs, entries}
+	case ClientClaudeDesktop, ClientCursor:
+		doc = struct {
+			MCPServers map[string]clientServer `json:"mcpServers"`
+		}{entries}
+	default:
+		return nil, fmt.Errorf("unsupported client format %q", format)
+	}
+
+	// Keep <NAME> placeholders readable
+	var data bytes.Buffer
+	encoder := json.NewEncoder(&data)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return nil, fmt.Errorf("failed to marshal client config: %w", err)
+	}
+	if format == ClientClaudeDesktop && len(e.inputs) > 0 {
+		e.warnings = append(e.warnings, "Claude Desktop has no input prompts; replace the <NAME> placeholders with values")
+	}
+	return &ExportResult{JSON: strings.TrimRight(data.String(), "\n"), Inputs: e.inputs, Warnings: e.warnings}, nil
+}
+
+// image runs the server with docker run. Secrets and env are forwarded with
+// -e NAME so their values stay in the client's env block.
+func (e *clientExporter) image(server catalog.Server) clientServer {
+	args := []string{"run", "--rm", "-i", "--init", "--security-opt", "no-new-privileges"}
+	if server.DisableNetwork {
+		args = append(args, "--network", "none")
+	}
+	env := make(map[string]string)
+	for _, secret := range server.Secrets {
+		args = append(args, "-e", secret.Env)
+		env[secret.Env] = e.reference(e.secretInput(server, secret))
+	}
+	for _, variable := range server.Env {
+		args = append(args, "-e", variable.Name)
+		env[variable.Name] = e.resolve(server, variable.Value)
+	}
+	for _, volume := range server.Volumes {
+		args = append(args, "-v", e.resolve(server, volume))
+	}
+	if server.User != "" {
+		args = append(args, "-u", server.User)
+	}
+	for _, host := range server.ExtraHosts {
+		args = append(args, "--add-host", host)
+	}
+	args = append(args, server.Image)
+	for _, arg := range server.Command {
+		args = append(args, e.resolve(server, arg))
+	}
+
+	entry := clientServer{Command: "docker", Args: args}
+	if len(env) > 0 {
+		entry.Env = env
+	}
+	if e.format == ClientVSCode {
+		entry.Type = "stdio"
+	}
+	return entry
+}
+
+func (e *clientExporter) remote(server catalog.Server) clientServer {
+	url := e.resolve(server, server.Remote.URL)
+	headers := make(map[string]string, len(server.Remote.Headers))
+	for _, name := range sortedMapKeys(server.Remote.Headers) {
+		headers[name] = e.resolve(server, server.Remote.Headers[name])
+	}
+	if server.OAuth != nil && len(server.OAuth.Providers) > 0 {
+		e.warnings = append(e.warnings, fmt.Sprintf("%s uses OAuth; the client has to authorize it", server.Name))
+	}
+
+	if e.format == ClientClaudeDesktop {
+		// Claude Desktop only starts stdio servers; mcp-remote bridges to the
+		// remote and reads header values from its env
+		args := []string{"-y", "mcp-remote", url}
+		if server.Remote.Transport == "sse" {
+			args = append(args, "--transport", "sse-only")
+		}
+		env := make(map[string]string)
+		for _, name := range sortedMapKeys(headers) {
+			headerEnv := "HEADER_" + envName(name)
+			args = append(args, "--header", fmt.Sprintf("%s:${%s}", name, headerEnv))
+			env[headerEnv] = headers[name]
+		}
+		entry := clientServer{Command: "npx", Args: args}
+		if len(env) > 0 {
+			entry.Env = env
+		}
+		return entry
+	}
+
+	entry := clientServer{URL: url}
+	if len(headers) > 0 {
+		entry.Headers = headers
+	}
+	if e.format == ClientVSCode {
+		entry.Type = "http"
+		if server.Remote.Transport == "sse" {
+			entry.Type = "sse"
+		}
+	}
+	return entry
+}
+
+// resolve replaces secret (${NAME}) and config ({{server.key}}) references
+// with the format's reference to the matching input
+func (e *clientExporter) resolve(server catalog.Server, value string) string {
+	result, _ := interpolate(value, func(ref string) (string, bool) {
+		for _, secret := range server.Secrets {
+			if secret.Env == ref {
+				return e.reference(e.secretInput(server, secret)), true
+			}
+		}
+		return e.reference(e.configInput(server, ref)), true
+	})
+	return result
+}
+
+// reference is how the format refers to an input
+func (e *clientExporter) reference(input ExportInput) string {
+	switch e.format {
+	case ClientVSCode:
+		return "${input:" + input.ID + "}"
+	case ClientCursor:
+		return "${env:" + input.Env + "}"
+	}
+	return "<" + input.Env + ">"
+}
+
+func (e *clientExporter) secretInput(server catalog.Server, secret catalog.Secret) ExportInput {
+	return e.addInput(ExportInput{
+		ID:          secret.Name,
+		Env:         secret.Env,
+		Description: fmt.Sprintf("%s for %s", secret.Env, server.Name),
+		Secret:      true,
+	})
+}
+
+func (e *clientExporter) configInput(server catalog.Server, ref string) ExportInput {
+	key := ref
+	if prefix := server.Name + "."; strings.HasPrefix(ref, prefix) {
+		key = strings.TrimPrefix(ref, prefix)
+	}
+	input := ExportInput{ID: server.Name + "." + key, Env: envName(key)}
+	for _, property := range serverConfig(server) {
+		if property.Name == key {
+			input.Description = property.Description
+		}
+	}
+	if input.Description == "" {
+		input.Description = fmt.Sprintf("%s for %s", key, server.Name)
+	}
+	return e.addInput(input)
+}
+
+func (e *clientExporter) addInput(input ExportInput) ExportInput {
+	if !e.seen[input.ID] {
+		e.seen[input.ID] = true
+		e.inputs = append(e.inputs, input)
+	}
+	return input
+}
+
+// envName turns a config key or header name into an environment variable name
+func envName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, name)
+}
@@ -0,0 +1,176 @@
+package catalogs
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+func decodeExport(t *testing.T, result *ExportResult) map[string]any {
+	t.Helper()
+	var doc map[string]any
+	if err := json.Unmarshal([]byte(result.JSON), &doc); err != nil {
+		t.Fatalf("Export is not JSON: %v\n%s", err, result.JSON)
+	}
+	return doc
+}
+
+func TestExportVSCode(t *testing.T) {
+	result, err := ExportClientConfig(ClientVSCode, []catalog.Server{
+		{
+			Name:           "com-example-notes",
+			Type:           "server",
+			Image:          "docker.io/example/notes:1.0.0",
+			Secrets:        []catalog.Secret{{Name: "com-example-notes.api_key", Env: "API_KEY"}},
+			Env:            []catalog.Env{{Name: "REGION", Value: "{{com-example-notes.region}}"}},
+			Volumes:        []string{"{{com-example-notes.data}}:/data"},
+			User:           "1000:1000",
+			DisableNetwork: true,
+			Command:        []string{"--verbose"},
+		},
+		{
+			Name: "com-example-remote",
+			Type: "remote",
+			Remote: catalog.Remote{
+				URL:       "https://mcp.example.com/mcp",
+				Transport: "sse",
+				Headers:   map[string]string{"Authorization": "Bearer ${TOKEN}"},
+			},
+			Secrets: []catalog.Secret{{Name: "com-example-remote.token", Env: "TOKEN"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("ExportClientConfig failed: %v", err)
+	}
+	doc := decodeExport(t, result)
+
+	servers := doc["servers"].(map[string]any)
+	notes := servers["com-example-notes"].(map[string]any)
+	expectedArgs := []any{"run", "--rm", "-i", "--init", "--security-opt", "no-new-privileges", "--network", "none",
+		"-e", "API_KEY", "-e", "REGION", "-v", "${input:com-example-notes.data}:/data", "-u", "1000:1000",
+		"docker.io/example/notes:1.0.0", "--verbose"}
+	if notes["type"] != "stdio" || notes["command"] != "docker" || !reflect.DeepEqual(notes["args"], expectedArgs) {
+		t.Errorf("Unexpected image server %v", notes)
+	}
+	expectedEnv := map[string]any{"API_KEY": "${input:com-example-notes.api_key}", "REGION": "${input:com-example-notes.region}"}
+	if !reflect.DeepEqual(notes["env"], expectedEnv) {
+		t.Errorf("Unexpected env %v", notes["env"])
+	}
+
+	remote := servers["com-example-remote"].(map[string]any)
+	if remote["type"] != "sse" || remote["url"] != "https://mcp.example.com/mcp" {
+		t.Errorf("Unexpected remote %v", remote)
+	}
+	if headers := remote["headers"].(map[string]any); headers["Authorization"] != "Bearer ${input:com-example-remote.token}" {
+		t.Errorf("Unexpected headers %v", headers)
+	}
+
+	inputs := doc["inputs"].([]any)
+	if len(inputs) != 4 {
+		t.Fatalf("Expected 4 inputs, got %v", inputs)
+	}
+	first := inputs[0].(map[string]any)
+	if first["id"] != "com-example-notes.api_key" || first["password"] != true || first["type"] != "promptString" {
+		t.Errorf("Unexpected secret input %v", first)
+	}
+	if _, ok := inputs[1].(map[string]any)["password"]; ok {
+		t.Errorf("Config input should not be a password: %v", inputs[1])
+	}
+}
+
+func TestExportCursor(t *testing.T) {
+	result, err := ExportClientConfig(ClientCursor, []catalog.Server{
+		{
+			Name:    "com-example-notes",
+			Type:    "server",
+			Image:   "docker.io/example/notes:1.0.0",
+			Secrets: []catalog.Secret{{Name: "com-example-notes.api_key", Env: "API_KEY"}},
+			Env:     []catalog.Env{{Name: "REGION", Value: "{{com-example-notes.region}}"}},
+		},
+		{
+			Name:    "com-example-remote",
+			Type:    "remote",
+			Remote:  catalog.Remote{URL: "https://mcp.example.com/mcp", Headers: map[string]string{"Authorization": "Bearer ${TOKEN}"}},
+			Secrets: []catalog.Secret{{Name: "com-example-remote.token", Env: "TOKEN"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("ExportClientConfig failed: %v", err)
+	}
+	servers := decodeExport(t, result)["mcpServers"].(map[string]any)
+
+	notes := servers["com-example-notes"].(map[string]any)
+	if env := notes["env"].(map[string]any); env["API_KEY"] != "${env:API_KEY}" || env["REGION"] != "${env:REGION}" {
+		t.Errorf("Unexpected env %v", env)
+	}
+	remote := servers["com-example-remote"].(map[string]any)
+	if _, ok := remote["type"]; ok || remote["headers"].(map[string]any)["Authorization"] != "Bearer ${env:TOKEN}" {
+		t.Errorf("Unexpected remote %v", remote)
+	}
+}
+
+func TestExportClaudeDesktop(t *testing.T) {
+	result, err := ExportClientConfig(ClientClaudeDesktop, []catalog.Server{
+		{
+			Name:    "com-example-notes",
+			Type:    "server",
+			Image:   "docker.io/example/notes:1.0.0",
+			Secrets: []catalog.Secret{{Name: "com-example-notes.api_key", Env: "API_KEY"}},
+		},
+		{
+			Name: "com-example-remote",
+			Type: "remote",
+			Remote: catalog.Remote{
+				URL:       "https://mcp.example.com/mcp",
+				Transport: "sse",
+				Headers:   map[string]string{"Authorization": "Bearer ${TOKEN}"},
+			},
+			Secrets: []catalog.Secret{{Name: "com-example-remote.token", Env: "TOKEN"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("ExportClientConfig failed: %v", err)
+	}
+	if !strings.Contains(result.JSON, `"<API_KEY>"`) {
+		t.Errorf("Expected unescaped placeholders, got %s", result.JSON)
+	}
+	servers := decodeExport(t, result)["mcpServers"].(map[string]any)
+
+	remote := servers["com-example-remote"].(map[string]any)
+	expectedArgs := []any{"-y", "mcp-remote", "https://mcp.example.com/mcp", "--transport", "sse-only",
+		"--header", "Authorization:${HEADER_AUTHORIZATION}"}
+	if remote["command"] != "npx" || !reflect.DeepEqual(remote["args"], expectedArgs) {
+		t.Errorf("Unexpected remote %v", remote)
+	}
+	if env := remote["env"].(map[string]any); env["HEADER_AUTHORIZATION"] != "Bearer <TOKEN>" {
+		t.Errorf("Unexpected env %v", env)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "no input prompts") {
+		t.Errorf("Expected placeholder warning, got %v", result.Warnings)
+	}
+}
+
+func TestExportSkipsServersWithoutImageOrRemote(t *testing.T) {
+	result, err := ExportClientConfig(ClientCursor, []catalog.Server{{Name: "com-example-poci", Type: "poci"}})
+	if err != nil {
+		t.Fatalf("ExportClientConfig failed: %v", err)
+	}
+	if servers := decodeExport(t, result)["mcpServers"].(map[string]any); len(servers) != 0 {
+		t.Errorf("Expected no servers, got %v", servers)
+	}
+	if len(result.Warnings) != 1 {
+		t.Errorf("Expected a skip warning, got %v", result.Warnings)
+	}
+}
+
+func TestParseClientFormat(t *testing.T) {
+	if _, err := ParseClientFormat("zed"); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+	if format, err := ParseClientFormat("vscode"); err != nil || format != ClientVSCode {
+		t.Errorf("Unexpected %v, %v", format, err)
+	}
+}
@@ -0,0 +1,544 @@
+package catalogs
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	v0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// ImportedServer is one server of an MCP client configuration in registry
+// and catalog form
+type ImportedServer struct {
+	// Key is the server's name in the client configuration
+	Key     string
+	Server  v0.ServerJSON
+	Catalog *catalog.Server
+	// Unresolved lists what could not be inferred and has to be filled in
+	Unresolved []string
+}
+
+// ImportResult is the outcome of ImportClientConfig
+type ImportResult struct {
+	// Servers are sorted by key
+	Servers []ImportedServer
+	// Errors are keyed by client config key, for servers that could not be
+	// imported, e.g. because of a flag docker run does not have
+	Errors map[string]error
+}
+
+// clientConfig covers claude_desktop_config.json and .cursor/mcp.json
+// (mcpServers) as well as .vscode/mcp.json (servers and inputs)
+type clientConfig struct {
+	MCPServers map[string]clientServer `json:"mcpServers"`
+	Servers    map[string]clientServer `json:"servers"`
+	Inputs     []vscodeInput           `json:"inputs"`
+}
+
+var (
+	// clientReferencePattern matches ${input:id} (VS Code), ${env:NAME}
+	// (Cursor), ${NAME} (mcp-remote) and <NAME> (exported placeholders)
+	clientReferencePattern = regexp.MustCompile(`\$\{(?:input:|env:)?([A-Za-z0-9_.-]+)\}|<([A-Za-z0-9_]+)>`)
+	secretNamePattern      = regexp.MustCompile(`(?i)(token|secret|passw|api[_-]?key|apikey|credential|private[_-]?key|auth|_pat$)`)
+	nonNamePattern         = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+	nonVariablePattern     = regexp.MustCompile(`[^a-zA-Z0-9_]+`)
+)
+
+// dockerValueFlags are the docker run flags that take a value
+var dockerValueFlags = setOf(
+	"--add-host", "--annotation", "-a", "--attach", "--blkio-weight", "--blkio-weight-device",
+	"--cap-add", "--cap-drop", "--cgroup-parent", "--cgroupns", "--cidfile",
+	"--cpu-period", "--cpu-quota", "--cpu-rt-period", "--cpu-rt-runtime", "-c", "--cpu-shares",
+	"--cpus", "--cpuset-cpus", "--cpuset-mems", "--detach-keys",
+	"--device", "--device-cgroup-rule", "--device-read-bps", "--device-read-iops",
+	"--device-write-bps", "--device-write-iops", "--dns", "--dns-option", "--dns-search",
+	"--domainname", "--entrypoint", "-e", "--env", "--env-file", "--expose", "--gpus", "--group-add",
+	"--health-cmd", "--health-interval", "--health-retries", "--health-start-interval",
+	"--health-start-period", "--health-timeout", "-h", "--hostname", "--ip", "--ip6", "--ipc",
+	"--isolation", "--kernel-memory", "-l", "--label", "--label-file", "--link", "--link-local-ip",
+	"--log-driver", "--log-opt", "--mac-address", "-m", "--memory", "--memory-reservation",
+	"--memory-swap", "--memory-swappiness", "--mount", "--name", "--network", "--net",
+	"--network-alias", "--net-alias", "--oom-score-adj", "--pid", "--pids-limit", "--platform",
+	"-p", "--publish", "--pull", "--restart", "--runtime", "--security-opt", "--shm-size",
+	"--stop-signal", "--stop-timeout", "--storage-opt", "--sysctl", "--tmpfs", "--ulimit",
+	"-u", "--user", "--userns", "--uts", "-v", "--volume", "--volume-driver", "--volumes-from",
+	"-w", "--workdir",
+)
+
+// dockerBoolFlags are the docker run flags that take no value
+var dockerBoolFlags = setOf(
+	"-d", "--detach", "--disable-content-trust", "--help", "--init", "-i", "--interactive",
+	"--no-healthcheck", "--oom-kill-disable", "--privileged", "-P", "--publish-all", "-q", "--quiet",
+	"--read-only", "--rm", "--sig-proxy", "-t", "--tty",
+)
+
+// runnerValueFlags are the npx and uvx options that take a value
+var runnerValueFlags = map[string]map[string]bool{
+	"npx": setOf("-p", "--package", "-c", "--call", "--registry", "--cache", "--userconfig", "--prefix",
+		"--loglevel", "-w", "--workspace"),
+	"uvx": setOf("--from", "--with", "--with-editable", "--with-requirements", "-p", "--python",
+		"--index", "--default-index", "-i", "--index-url", "--extra-index-url", "-f", "--find-links",
+		"--index-strategy", "--keyring-provider", "--resolution", "--prerelease", "--exclude-newer",
+		"-C", "--config-setting", "--link-mode", "--reinstall-package", "--refresh-package",
+		"--no-binary-package", "--no-build-package", "--python-preference", "-c", "--constraints",
+		"--overrides", "-b", "--build-constraints", "--directory", "--project", "--cache-dir",
+		"--config-file", "--color", "--allow-insecure-host", "--env-file"),
+}
+
+// runnerBoolFlags are the npx and uvx options that take no value
+var runnerBoolFlags = map[string]map[string]bool{
+	"npx": setOf("-y", "--yes", "--no", "-q", "--quiet", "--ignore-existing", "--no-install",
+		"--prefer-offline", "--prefer-online", "--offline"),
+	"uvx": setOf("-q", "--quiet", "-v", "--verbose", "-n", "--no-cache", "--offline", "--isolated",
+		"--refresh", "--reinstall", "-U", "--upgrade", "--no-build", "--no-binary", "--no-index",
+		"--no-sources", "--compile-bytecode", "--no-python-downloads", "--no-config", "--no-progress",
+		"--native-tls", "--no-env-file"),
+}
+
+func setOf(items ...string) map[string]bool {
+	set := make(map[string]bool, len(items))
+	for _, item := range items {
+		set[item] = true
+	}
+	return set
+}
+
+// isBoolFlags reports whether an argument is a boolean flag of the table,
+// including combined short flags such as -it
+func isBoolFlags(table map[string]bool, arg string) bool {
+	if table[arg] {
+		return true
+	}
+	if strings.HasPrefix(arg, "--") || len(arg) < 3 {
+		return false
+	}
+	for _, c := range arg[1:] {
+		if !table["-"+string(c)] {
+			return false
+		}
+	}
+	return true
+}
+
+// ImportClientConfig parses an MCP client configuration and infers a
+// registry server.json and catalog entry for each server: the image, env,
+// volumes and user from docker run, the npm or pypi package from npx or uvx,
+// and a remote from url entries.
+func ImportClientConfig(data []byte) (*ImportResult, error) {
+	var config clientConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse client config: %w", err)
+	}
+	servers := config.MCPServers
+	if servers == nil {
+		servers = config.Servers
+	}
+	if servers == nil {
+		return nil, fmt.Errorf("client config has no mcpServers or servers")
+	}
+	inputs := make(map[string]vscodeInput, len(config.Inputs))
+	for _, input := range config.Inputs {
+		inputs[input.ID] = input
+	}
+
+	result := &ImportResult{Errors: make(map[string]error)}
+	for _, key := range sortedMapKeys(servers) {
+		imported, err := importClientServer(key, servers[key], inputs)
+		if err != nil {
+			result.Errors[key] = err
+			continue
+		}
+		result.Servers = append(result.Servers, *imported)
+	}
+	return result, nil
+}
+
+// clientImporter infers one server, recording what it could not
+type clientImporter struct {
+	entry      clientServer
+	inputs     map[string]vscodeInput
+	unresolved []string
+	// variables are the {variable} names used so far
+	variables map[string]bool
+}
+
+func (i *clientImporter) report(format string, args ...any) {
+	i.unresolved = append(i.unresolved, fmt.Sprintf(format, args...))
+}
+
+func importClientServer(key string, entry clientServer, inputs map[string]vscodeInput) (*ImportedServer, error) {
+	i := &clientImporter{entry: entry, inputs: inputs, variables: make(map[string]bool)}
+	name := nonNamePattern.ReplaceAllString(key, "-")
+	server := v0.ServerJSON{
+		Schema:  model.CurrentSchemaURL,
+		Name:    "local/" + name,
+		Title:   key,
+		Version: "0.0.0",
+	}
+	i.report("name: a reverse-DNS namespace is needed instead of local/")
+	i.report("description: not in the client config")
+
+	command := entry.Command
+	if j := strings.LastIndexAny(command, `/\`); j >= 0 {
+		command = command[j+1:]
+	}
+	switch {
+	case entry.URL != "":
+		server.Remotes = []model.Transport{i.remote(entry.URL, entry.Type, entry.Headers)}
+	case command == "docker" && len(entry.Args) > 0 && entry.Args[0] == "run":
+		pkg, err := i.docker(entry.Args[1:])
+		if err != nil {
+			return nil, err
+		}
+		server.Packages = []model.Package{pkg}
+		if pkg.Version != "" && pkg.Version != "latest" && !strings.HasPrefix(pkg.Version, "sha256:") {
+			server.Version = pkg.Version
+		}
+	case command == "npx" || command == "uvx":
+		remote, pkg, err := i.runner(command, entry.Args)
+		if err != nil {
+			return nil, err
+		}
+		if remote != nil {
+			server.Remotes = []model.Transport{*remote}
+		} else if pkg != nil {
+			server.Packages = []model.Package{*pkg}
+			if pkg.Version != "" {
+				server.Version = pkg.Version
+			}
+		}
+	default:
+		i.report("command %q is not docker run, npx or uvx; no package could be inferred", entry.Command)
+	}
+	if server.Version == "0.0.0" {
+		i.report("version: not in the client config")
+	}
+
+	transformed, err := transform(server, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, warning := range transformed.Warnings {
+		i.report("%s", warning)
+	}
+	if len(server.Packages) > 0 && server.Packages[0].RegistryType != model.RegistryTypeOCI {
+		i.report("image: %s package %s is not an image, so the catalog entry cannot run it", server.Packages[0].RegistryType, server.Packages[0].Identifier)
+	}
+
+	return &ImportedServer{Key: key, Server: server, Catalog: transformed.Server, Unresolved: i.unresolved}, nil
+}
+
+// docker infers an OCI package from docker run arguments. Flags docker run
+// does not have are an error: there is no telling whether they take a value,
+// so which argument is the image.
+func (i *clientImporter) docker(args []string) (model.Package, error) {
+	pkg := model.Package{
+		RegistryType: model.RegistryTypeOCI,
+		RunTimeHint:  "docker",
+		Transport:    model.Transport{Type: model.TransportTypeStdio},
+	}
+
+	image := -1
+	for j := 0; j < len(args); j++ {
+		arg := args[j]
+		if !strings.HasPrefix(arg, "-") {
+			image = j
+			break
+		}
+		flag, value, hasValue := strings.Cut(arg, "=")
+		switch {
+		case dockerValueFlags[flag] && !hasValue:
+			if j+1 == len(args) {
+				return pkg, fmt.Errorf("docker flag %s has no value", flag)
+			}
+			j++
+			value = args[j]
+		case dockerValueFlags[flag], isBoolFlags(dockerBoolFlags, flag):
+		default:
+			return pkg, fmt.Errorf("unknown docker run flag %s", flag)
+		}
+
+		switch flag {
+		case "-e", "--env":
+			envName, envValue, ok := strings.Cut(value, "=")
+			if !ok {
+				// -e NAME takes the value from the client's env block
+				envValue, ok = i.entry.Env[envName]
+			}
+			pkg.EnvironmentVariables = append(pkg.EnvironmentVariables, i.env(envName, envValue, ok))
+		case "-v", "--volume", "--mount", "-u", "--user", "--add-host":
+			pkg.RuntimeArguments = append(pkg.RuntimeArguments, i.argument(model.ArgumentTypeNamed, flag, value))
+		case "--network", "--net":
+			if value == "none" {
+				pkg.RuntimeArguments = append(pkg.RuntimeArguments, i.argument(model.ArgumentTypeNamed, flag, value))
+			} else {
+				i.report("docker flag %s %s has no catalog equivalent", flag, value)
+			}
+		default:
+			if !impliedRuntimeFlags[flag] && flag != "--security-opt" && flag != "--name" {
+				i.report("docker flag %s has no catalog equivalent", strings.TrimSpace(flag+" "+value))
+			}
+		}
+	}
+	if image < 0 {
+		return pkg, fmt.Errorf("docker run has no image")
+	}
+
+	pkg.Identifier, pkg.Version = splitImageReference(args[image])
+	if pkg.Version == "" {
+		pkg.Version = "latest"
+		i.report("image %s has no tag or digest; pin one", args[image])
+	}
+	for _, arg := range args[image+1:] {
+		pkg.PackageArguments = append(pkg.PackageArguments, i.argument(model.ArgumentTypePositional, "", arg))
+	}
+	return pkg, nil
+}
+
+// splitImageReference splits an image into repository and tag or digest
+func splitImageReference(image string) (string, string) {
+	if repository, digest, ok := strings.Cut(image, "@"); ok {
+		return repository, digest
+	}
+	if j := strings.LastIndex(image, ":"); j > strings.LastIndex(image, "/") {
+		return image[:j], image[j+1:]
+	}
+	return image, ""
+}
+
+// runner infers an npm (npx) or pypi (uvx) package. npx mcp-remote is
+// a remote behind a stdio bridge. Options the runner does not have are an
+// error, like unknown docker flags.
+func (i *clientImporter) runner(command string, args []string) (*model.Transport, *model.Package, error) {
+	var spec string
+	var rest []string
+	for j := 0; j < len(args); j++ {
+		arg := args[j]
+		if !strings.HasPrefix(arg, "-") {
+			if spec == "" {
+				spec = arg
+			} else {
+				// uvx --from package command: the package's executable
+				i.report("%s command %s: the registry has no field for the package executable", command, arg)
+			}
+			rest = args[j+1:]
+			break
+		}
+
+		if arg == "--" {
+			continue
+		}
+		option, value, hasValue := strings.Cut(arg, "=")
+		switch {
+		case runnerValueFlags[command][option]:
+			if !hasValue {
+				if j+1 == len(args) {
+					return nil, nil, fmt.Errorf("%s option %s has no value", command, option)
+				}
+				j++
+				value = args[j]
+			}
+			switch {
+			case option == "--from" || (command == "npx" && (option == "-p" || option == "--package")):
+				spec = value
+			default:
+				i.report("%s option %s %s has no registry equivalent", command, option, value)
+			}
+		case isBoolFlags(runnerBoolFlags[command], option):
+		default:
+			return nil, nil, fmt.Errorf("unknown %s option %s", command, option)
+		}
+	}
+	if spec == "" {
+		i.report("%s has no package", command)
+		return nil, nil, nil
+	}
+
+	pkg := &model.Package{RunTimeHint: command, Transport: model.Transport{Type: model.TransportTypeStdio}}
+	if command == "npx" {
+		pkg.RegistryType = model.RegistryTypeNPM
+		pkg.RegistryBaseURL = "https://registry.npmjs.org"
+		pkg.Identifier, pkg.Version = spec, ""
+		if j := strings.LastIndex(spec, "@"); j > 0 {
+			pkg.Identifier, pkg.Version = spec[:j], spec[j+1:]
+		}
+		if pkg.Identifier == "mcp-remote" && len(rest) > 0 {
+			return i.mcpRemote(rest), nil, nil
+		}
+	} else {
+		pkg.RegistryType = model.RegistryTypePyPI
+		pkg.RegistryBaseURL = "https://pypi.org"
+		pkg.Identifier, pkg.Version = spec, ""
+		for _, sep := range []string{"==", "@"} {
+			if name, version, ok := strings.Cut(spec, sep); ok {
+				pkg.Identifier, pkg.Version = name, version
+				break
+			}
+		}
+	}
+	if pkg.Version == "" || pkg.Version == "latest" {
+		pkg.Version = ""
+		i.report("version: %s package %s is not pinned", pkg.RegistryType, pkg.Identifier)
+	}
+
+	for _, arg := range rest {
+		pkg.PackageArguments = append(pkg.PackageArguments, i.argument(model.ArgumentTypePositional, "", arg))
+	}
+	for _, name := range sortedMapKeys(i.entry.Env) {
+		pkg.EnvironmentVariables = append(pkg.EnvironmentVariables, i.env(name, i.entry.Env[name], true))
+	}
+	return nil, pkg, nil
+}
+
+// mcpRemote reads the URL and --header flags of npx mcp-remote
+func (i *clientImporter) mcpRemote(args []string) *model.Transport {
+	url := args[0]
+	transport := "streamable-http"
+	headers := make(map[string]string)
+	for j := 1; j < len(args); j++ {
+		switch args[j] {
+		case "--header":
+			if j+1 < len(args) {
+				name, value, _ := strings.Cut(args[j+1], ":")
+				headers[strings.TrimSpace(name)] = i.expandEnv(strings.TrimSpace(value))
+				j++
+			}
+		case "--transport":
+			if j+1 < len(args) && strings.HasPrefix(args[j+1], "sse") {
+				transport = "sse"
+			}
+			j++
+		}
+	}
+	remote := i.remote(url, transport, headers)
+	return &remote
+}
+
+// expandEnv fills ${NAME} from the client's env block, as mcp-remote does
+func (i *clientImporter) expandEnv(value string) string {
+	return clientReferencePattern.ReplaceAllStringFunc(value, func(match string) string {
+		name := clientReference(match)
+		if v, ok := i.entry.Env[name]; ok && strings.HasPrefix(match, "${") && !strings.Contains(match, ":") {
+			return v
+		}
+		return match
+	})
+}
+
+func (i *clientImporter) remote(url, transportType string, headers map[string]string) model.Transport {
+	remote := model.Transport{Type: model.TransportTypeStreamableHTTP}
+	if transportType == "sse" {
+		remote.Type = model.TransportTypeSSE
+	}
+	input := i.input(url, false, "url")
+	remote.URL = input.Value
+	remote.Variables = input.Variables
+	for _, name := range sortedMapKeys(headers) {
+		remote.Headers = append(remote.Headers, model.KeyValueInput{
+			Name:               name,
+			InputWithVariables: i.input(headers[name], secretNamePattern.MatchString(name), name),
+		})
+	}
+	return remote
+}
+
+// env converts an environment variable. Values of secret-looking names are
+// never imported; references become secret or config declarations.
+func (i *clientImporter) env(name, value string, known bool) model.KeyValueInput {
+	secret := secretNamePattern.MatchString(name)
+	declared := model.KeyValueInput{Name: name}
+	declared.IsRequired = true
+	switch {
+	case !known:
+		i.report("env %s: no value in the client config", name)
+		declared.IsSecret = secret
+		declared.Description = name
+	case clientReferencePattern.MatchString(value) && clientReferencePattern.FindString(value) == value:
+		input := i.referencedInput(clientReference(value), secret)
+		input.IsRequired = true
+		declared.Input = input
+	case secret:
+		i.report("env %s: secret value not imported", name)
+		declared.IsSecret = true
+		declared.Description = name
+	default:
+		declared.InputWithVariables = i.input(value, false, name)
+		declared.IsRequired = false
+	}
+	return declared
+}
+
+// argument converts a docker or package argument, turning references into
+// variables
+func (i *clientImporter) argument(argType model.ArgumentType, name, value string) model.Argument {
+	return model.Argument{Type: argType, Name: name, InputWithVariables: i.input(value, false, name)}
+}
+
+// input replaces client references in a value with {variables}. A
+// secret-looking literal is replaced entirely so its value is not imported;
+// its variable is named after the header or env var it came from.
+func (i *clientImporter) input(value string, secret bool, name string) model.InputWithVariables {
+	var result model.InputWithVariables
+	if secret && !clientReferencePattern.MatchString(value) {
+		i.report("secret value %s not imported", redact(value))
+		variable := i.secretVariable(name)
+		result.Value = "{" + variable + "}"
+		result.Variables = map[string]model.Input{variable: {IsSecret: true, IsRequired: true}}
+		if scheme, _, ok := strings.Cut(value, " "); ok && strings.EqualFold(scheme, "bearer") {
+			result.Value = "Bearer {" + variable + "}"
+		}
+		return result
+	}
+
+	result.Value = clientReferencePattern.ReplaceAllStringFunc(value, func(match string) string {
+		ref := clientReference(match)
+		variable := strings.ToLower(nonVariablePattern.ReplaceAllString(ref[strings.LastIndex(ref, ".")+1:], "_"))
+		if result.Variables == nil {
+			result.Variables = make(map[string]model.Input)
+		}
+		input := i.referencedInput(ref, secret)
+		input.IsRequired = true
+		result.Variables[variable] = input
+		i.variables[variable] = true
+		return "{" + variable + "}"
+	})
+	return result
+}
+
+// secretVariable names the variable of a secret literal: token for an
+// Authorization header, the lower-cased name otherwise (X-Api-Key becomes
+// x_api_key), with a suffix when the name is taken
+func (i *clientImporter) secretVariable(name string) string {
+	base := strings.Trim(strings.ToLower(nonVariablePattern.ReplaceAllString(name, "_")), "_")
+	if base == "" || base == "authorization" {
+		base = "token"
+	}
+	variable := base
+	for n := 2; i.variables[variable]; n++ {
+		variable = fmt.Sprintf("%s_%d", base, n)
+	}
+	i.variables[variable] = true
+	return variable
+}
+
+// referencedInput describes a VS Code input, or a name that is a secret if
+// it looks like one
+func (i *clientImporter) referencedInput(ref string, secret bool) model.Input {
+	if input, ok := i.inputs[ref]; ok {
+		return model.Input{Description: input.Description, IsSecret: input.Password}
+	}
+	return model.Input{Description: ref, IsSecret: secret || secretNamePattern.MatchString(ref)}
+}
+
+// clientReference is the input id or env name of a client reference
+func clientReference(match string) string {
+	groups := clientReferencePattern.FindStringSubmatch(match)
+	if groups[1] != "" {
+		return groups[1]
+	}
+	return groups[2]
+}
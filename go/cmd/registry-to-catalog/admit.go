@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	transformer "github.com/slimslenderslacks/catalogs"
+)
+
+func admit(args []string) {
+	flags := flag.NewFlagSet("admit", flag.ExitOnError)
+	catalogPath := flags.String("catalog", "", "Catalog file or catalog/ directory")
+	policyFile := flags.String("policy", "", "Admission policy YAML")
+	flags.Parse(args)
+
+	if *catalogPath == "" || *policyFile == "" {
+		fmt.Fprintln(os.Stderr, "Error: -catalog and -policy are required")
+		flags.Usage()
+		os.Exit(1)
+	}
+
+	policy, err := transformer.LoadAdmissionPolicy(*policyFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading admission policy: %v\n", err)
+		os.Exit(1)
+	}
+	servers, err := transformer.LoadCatalog(*catalogPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading catalog: %v\n", err)
+		os.Exit(1)
+	}
+
+	rejected := 0
+	for _, name := range transformer.SortedServerNames(servers) {
+		result := policy.Evaluate(servers[name])
+		fmt.Println(name)
+		for _, rule := range result.Results {
+			fmt.Printf("  %s\n", rule)
+		}
+		if !result.Admitted() {
+			rejected++
+		}
+	}
+
+	if rejected > 0 {
+		fmt.Fprintf(os.Stderr, "%d of %d servers rejected by admission policy\n", rejected, len(servers))
+		os.Exit(1)
+	}
+}
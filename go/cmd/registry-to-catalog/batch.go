@@ -0,0 +1,87 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"text/template"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	transformer "github.com/slimslenderslacks/catalogs"
+)
+
+func batch(args []string) {
+	flags := flag.NewFlagSet("batch", flag.ExitOnError)
+	inputFile := flags.String("input", "", "Registry list response JSON, e.g. from /v0/servers (or - for stdin)")
+	outputFile := flags.String("output", "", "Output catalog JSON file (or - for stdout)")
+	name := flags.String("name", "", "Catalog name")
+	displayName := flags.String("display-name", "", "Catalog display name")
+	version := flags.String("version", "", "Pick versions with the resolver: latest, stable or a constraint like ^1.2 (default: isLatest)")
+	templateFile := flags.String("template", "", "Render the catalog with this Go text/template instead of writing JSON")
+	options := transformOptionFlags(flags)
+	flags.Parse(args)
+
+	opts := transformer.BatchOptions{
+		Transform:   options(),
+		Name:        *name,
+		DisplayName: *displayName,
+	}
+	var tmpl *template.Template
+	if *templateFile != "" {
+		tmpl = loadTemplate(*templateFile)
+	}
+	if *version != "" {
+		selector, err := transformer.ParseVersionSelector(*version)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		opts.Version = &selector
+	}
+
+	result, err := transformer.TransformBatchJSON(readInput(*inputFile), opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error transforming JSON: %v\n", err)
+		os.Exit(1)
+	}
+
+	printWarnings(result.Warnings)
+	for _, name := range sortedKeys(result.Results) {
+		for _, warning := range result.Results[name].Warnings {
+			fmt.Fprintf(os.Stderr, "Warning: %s: %s\n", name, warning)
+		}
+	}
+	for _, skipped := range result.Skipped {
+		fmt.Fprintf(os.Stderr, "Skipped %s %s: %s\n", skipped.Server, skipped.Version, skipped.Reason)
+	}
+	for _, name := range result.UnusedOverlays {
+		fmt.Fprintf(os.Stderr, "Warning: overlay %s targets a server that is not in the catalog\n", opts.Transform.Overlays[name].Source)
+	}
+	for _, name := range sortedKeys(result.Errors) {
+		fmt.Fprintf(os.Stderr, "Error transforming %s: %v\n", name, result.Errors[name])
+	}
+
+	if tmpl != nil {
+		servers := make(map[string]catalog.Server, len(result.Results))
+		for name, server := range result.Results {
+			servers[name] = *server.Server
+		}
+		writeTemplate(tmpl, transformer.NewTemplateCatalog(*name, *displayName, servers), *outputFile)
+	} else {
+		writeOutput(*outputFile, result.JSON, "catalog")
+	}
+
+	if len(result.Errors) > 0 {
+		os.Exit(1)
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	transformer "github.com/slimslenderslacks/catalogs"
+)
+
+// buildContexts writes a Dockerfile and server.json per npm or pypi server
+func buildContexts(args []string) {
+	flags := flag.NewFlagSet("build-context", flag.ExitOnError)
+	inputFile := flags.String("input", "", "Registry server JSON (or - for stdin)")
+	urlsFile := flags.String("urls", "", "File of registry version URLs, one per line (e.g. npm-urls.txt)")
+	outputDir := flags.String("output-dir", "", "Directory for <server>/Dockerfile and <server>/server.json")
+	timeout := flags.Duration("timeout", 30*time.Second, "HTTP timeout per URL")
+	var opts transformer.BuildContextOptions
+	flags.StringVar(&opts.ImagePrefix, "image-prefix", "mcp", "Repository the images are planned under")
+	flags.StringVar(&opts.NodeImage, "node-image", "node:22-alpine", "Base image of npm servers")
+	flags.StringVar(&opts.PythonImage, "python-image", "ghcr.io/astral-sh/uv:python3.12-bookworm-slim", "Base image of pypi servers (with uv)")
+	flags.Parse(args)
+
+	if *outputDir == "" {
+		fmt.Fprintln(os.Stderr, "Error: -output-dir is required")
+		flags.Usage()
+		os.Exit(1)
+	}
+
+	if *urlsFile == "" {
+		context, err := transformer.BuildContextJSON(readInput(*inputFile), opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		writeBuildContext(*outputDir, context)
+		return
+	}
+
+	urls, err := readURLs(*urlsFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", *urlsFile, err)
+		os.Exit(1)
+	}
+	client := &http.Client{Timeout: *timeout}
+	fetched := make(map[string]string)
+	for _, url := range urls {
+		data, err := fetchRegistryJSON(client, url)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Skipped %s: %v\n", url, err)
+			continue
+		}
+		fetched[url] = data
+	}
+
+	// The URLs list every version; one context directory holds one server
+	result := transformer.BuildContextsJSON(fetched, opts)
+	printWarnings(result.Warnings)
+	for _, skipped := range result.Skipped {
+		fmt.Fprintf(os.Stderr, "Skipped %s %s: %s\n", skipped.Server, skipped.Version, skipped.Reason)
+	}
+	for _, name := range sortedKeys(result.Errors) {
+		fmt.Fprintf(os.Stderr, "Skipped %s: %v\n", name, result.Errors[name])
+	}
+	for _, context := range result.Contexts {
+		writeBuildContext(*outputDir, context)
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %d build contexts from %d URLs\n", len(result.Contexts), len(urls))
+}
+
+func writeBuildContext(outputDir string, context *transformer.BuildContext) {
+	for _, warning := range context.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s: %s\n", context.Name, warning)
+	}
+	dir := filepath.Join(outputDir, context.Name)
+	writeImported(filepath.Join(dir, "Dockerfile"), []byte(context.Dockerfile))
+	writeImported(filepath.Join(dir, "server.json"), []byte(context.ServerJSON+"\n"))
+	fmt.Fprintf(os.Stderr, "Planned image %s\n", context.Image)
+}
+
+func readURLs(file string) ([]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var urls []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" && !strings.HasPrefix(line, "#") {
+			urls = append(urls, line)
+		}
+	}
+	return urls, scanner.Err()
+}
+
+func fetchRegistryJSON(client *http.Client, url string) (string, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	transformer "github.com/slimslenderslacks/catalogs"
+)
+
+func checkOverlays(args []string) {
+	flags := flag.NewFlagSet("check-overlays", flag.ExitOnError)
+	overlaysDir := flags.String("overlays", "", "Directory of <server-name>.yaml overlays")
+	catalogPath := flags.String("catalog", "", "Catalog file or catalog/ directory")
+	flags.Parse(args)
+
+	if *overlaysDir == "" || *catalogPath == "" {
+		fmt.Fprintln(os.Stderr, "Error: -overlays and -catalog are required")
+		flags.Usage()
+		os.Exit(1)
+	}
+
+	overlays, err := transformer.LoadOverlays(*overlaysDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading overlays: %v\n", err)
+		os.Exit(1)
+	}
+	servers, err := transformer.LoadCatalog(*catalogPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading catalog: %v\n", err)
+		os.Exit(1)
+	}
+
+	problems := transformer.ValidateOverlays(overlays, servers)
+	for _, name := range sortedKeys(overlays) {
+		if err, ok := problems[name]; ok {
+			fmt.Printf("FAIL %s: %v\n", overlays[name].Source, err)
+		} else {
+			fmt.Printf("OK   %s\n", overlays[name].Source)
+		}
+	}
+
+	if len(problems) > 0 {
+		fmt.Fprintf(os.Stderr, "%d of %d overlays no longer apply\n", len(problems), len(overlays))
+		os.Exit(1)
+	}
+}
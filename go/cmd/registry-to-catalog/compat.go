@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	transformer "github.com/slimslenderslacks/catalogs"
+)
+
+// compat exits 0 for no or cosmetic changes, 10 for additive and 11 for
+// breaking ones, once the -fail-on level is reached
+func compat(args []string) {
+	flags := flag.NewFlagSet("compat", flag.ExitOnError)
+	oldFile := flags.String("old", "", "Previous version: registry JSON or catalog server")
+	newFile := flags.String("new", "", "New version: registry JSON or catalog server")
+	failOn := flags.String("fail-on", "breaking", "Lowest level that sets the exit code: cosmetic, additive or breaking")
+	jsonOutput := flags.Bool("json", false, "Print the report as JSON")
+	flags.Parse(args)
+
+	if *oldFile == "" || *newFile == "" {
+		fmt.Fprintln(os.Stderr, "Error: -old and -new are required")
+		flags.Usage()
+		os.Exit(1)
+	}
+	threshold, err := transformer.ParseCompatibility(*failOn)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	report := transformer.AnalyzeCompatibility(readServerVersion(*oldFile), readServerVersion(*newFile))
+
+	if *jsonOutput {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error marshaling report: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+	} else {
+		for _, change := range report.Changes {
+			fmt.Println(change)
+		}
+		if report.Level == "" {
+			fmt.Printf("%s: no changes\n", report.Server)
+		} else {
+			fmt.Printf("%s: %s\n", report.Server, report.Level)
+		}
+	}
+
+	if report.Level.AtLeast(threshold) {
+		os.Exit(report.Level.ExitCode())
+	}
+}
+
+func readServerVersion(file string) catalog.Server {
+	server, err := transformer.ParseServerVersion([]byte(readInput(file)))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", file, err)
+		os.Exit(1)
+	}
+	return server
+}
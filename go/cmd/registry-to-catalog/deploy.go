@@ -0,0 +1,98 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	transformer "github.com/slimslenderslacks/catalogs"
+)
+
+// deploy writes a compose.yaml or Kubernetes manifests for catalog servers
+func deploy(args []string) {
+	flags := flag.NewFlagSet("deploy", flag.ExitOnError)
+	catalogPath := flags.String("catalog", "", "Catalog file, catalog server file or catalog/ directory")
+	target := flags.String("target", "compose", "Manifests to generate: compose or kubernetes")
+	servers := flags.String("servers", "", "Comma-separated server names to deploy (default: all)")
+	valuesFile := flags.String("values", "", "Config values YAML, as in the gateway's config.yaml")
+	outputFile := flags.String("output", "", "Output file (or - for stdout)")
+	opts := transformer.DeployOptions{Ports: make(map[string]int)}
+	flags.Func("port", "Container port of a server served over HTTP, as name=port (repeatable; default from metadata.containerTransport)", func(s string) error {
+		name, value, ok := strings.Cut(s, "=")
+		port, err := strconv.Atoi(value)
+		if !ok || err != nil {
+			return fmt.Errorf("expected name=port")
+		}
+		opts.Ports[name] = port
+		return nil
+	})
+	flags.StringVar(&opts.Namespace, "namespace", "", "Namespace of the Kubernetes manifests")
+	flags.StringVar(&opts.SecretStore, "secret-store", "mcp-secrets", "external-secrets store the Kubernetes secrets are read from")
+	flags.StringVar(&opts.SecretStoreKind, "secret-store-kind", "ClusterSecretStore", "Kind of the secret store: SecretStore or ClusterSecretStore")
+	flags.Parse(args)
+
+	if *catalogPath == "" {
+		fmt.Fprintln(os.Stderr, "Error: -catalog is required")
+		flags.Usage()
+		os.Exit(1)
+	}
+	deployTarget, err := transformer.ParseDeployTarget(*target)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if *valuesFile != "" {
+		readYAML(*valuesFile, &opts.Config, "values")
+	}
+
+	loaded, err := transformer.LoadCatalog(*catalogPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading catalog: %v\n", err)
+		os.Exit(1)
+	}
+	// Ports of images served over HTTP come from the catalog metadata
+	// unless -port overrides them
+	metadata, err := transformer.LoadCatalogMetadata(*catalogPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading catalog metadata: %v\n", err)
+		os.Exit(1)
+	}
+	for name, meta := range metadata {
+		if _, ok := opts.Ports[name]; !ok && meta.ContainerTransport != nil {
+			opts.Ports[name] = meta.ContainerTransport.Port
+		}
+	}
+
+	names := transformer.SortedServerNames(loaded)
+	if *servers != "" {
+		names = strings.Split(*servers, ",")
+	}
+	var selected []catalog.Server
+	for _, name := range names {
+		server, ok := loaded[strings.TrimSpace(name)]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: no server %q in the catalog\n", name)
+			os.Exit(1)
+		}
+		selected = append(selected, server)
+	}
+
+	generate := transformer.GenerateCompose
+	if deployTarget == transformer.DeployKubernetes {
+		generate = transformer.GenerateKubernetes
+	}
+	result, err := generate(selected, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	printWarnings(result.Warnings)
+	for _, secret := range result.Secrets {
+		fmt.Fprintf(os.Stderr, "Secret %s (%s): %s\n", secret.Name, secret.Env, secret.Reference)
+	}
+
+	writeOutput(*outputFile, strings.TrimRight(result.YAML, "\n"), string(deployTarget)+" manifests")
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	transformer "github.com/slimslenderslacks/catalogs"
+)
+
+func diff(args []string) {
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	oldPath := flags.String("old", "", "Old catalog file or catalog/ directory")
+	newPath := flags.String("new", "", "New catalog file or catalog/ directory")
+	format := flags.String("format", "text", "Output format: text, json or markdown")
+	outputFile := flags.String("output", "", "Output file (or - for stdout)")
+	flags.Parse(args)
+
+	if *oldPath == "" || *newPath == "" {
+		fmt.Fprintln(os.Stderr, "Error: -old and -new are required")
+		flags.Usage()
+		os.Exit(1)
+	}
+
+	oldServers, err := transformer.LoadCatalog(*oldPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading catalog: %v\n", err)
+		os.Exit(1)
+	}
+	newServers, err := transformer.LoadCatalog(*newPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading catalog: %v\n", err)
+		os.Exit(1)
+	}
+
+	result := transformer.DiffCatalogs(oldServers, newServers)
+
+	var content string
+	switch *format {
+	case "text":
+		content = result.Text()
+	case "markdown":
+		content = result.Markdown()
+	case "json":
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error marshaling diff: %v\n", err)
+			os.Exit(1)
+		}
+		content = string(data)
+	default:
+		fmt.Fprintf(os.Stderr, "Error: invalid format %q (expected text, json or markdown)\n", *format)
+		os.Exit(1)
+	}
+
+	writeOutput(*outputFile, strings.TrimRight(content, "\n"), "diff")
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	transformer "github.com/slimslenderslacks/catalogs"
+)
+
+type discoverOAuthResult struct {
+	OAuth        *catalog.OAuth                `json:"oauth"`
+	Registration transformer.OAuthRegistration `json:"registration"`
+}
+
+func discoverOAuth(args []string) {
+	flags := flag.NewFlagSet("discover-oauth", flag.ExitOnError)
+	remoteURL := flags.String("url", "", "Remote MCP server URL")
+	server := flags.String("server", "", "Catalog server name")
+	provider := flags.String("provider", "", "OAuth provider name (default: server name)")
+	outputFile := flags.String("output", "", "Output JSON file (or - for stdout)")
+	timeout := flags.Duration("timeout", 30*time.Second, "HTTP timeout")
+	flags.Parse(args)
+
+	if *remoteURL == "" || *server == "" {
+		fmt.Fprintln(os.Stderr, "Error: -url and -server are required")
+		flags.Usage()
+		os.Exit(1)
+	}
+	if *provider == "" {
+		*provider = *server
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	discovery, err := transformer.DiscoverOAuth(ctx, http.DefaultClient, *remoteURL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error discovering OAuth metadata: %v\n", err)
+		os.Exit(1)
+	}
+
+	result := discoverOAuthResult{
+		OAuth:        discovery.CatalogOAuth(*provider),
+		Registration: discovery.Registration(*server, *provider),
+	}
+
+	resultJSON, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error marshaling result: %v\n", err)
+		os.Exit(1)
+	}
+
+	writeOutput(*outputFile, string(resultJSON), "OAuth discovery")
+}
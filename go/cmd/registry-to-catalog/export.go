@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	transformer "github.com/slimslenderslacks/catalogs"
+)
+
+func export(args []string) {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	catalogPath := flags.String("catalog", "", "Catalog file, catalog server file or catalog/ directory")
+	format := flags.String("format", "vscode", "Client configuration format: claude-desktop, vscode or cursor")
+	servers := flags.String("servers", "", "Comma-separated server names to export (default: all)")
+	outputFile := flags.String("output", "", "Output file (or - for stdout)")
+	flags.Parse(args)
+
+	if *catalogPath == "" {
+		fmt.Fprintln(os.Stderr, "Error: -catalog is required")
+		flags.Usage()
+		os.Exit(1)
+	}
+	clientFormat, err := transformer.ParseClientFormat(*format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	loaded, err := transformer.LoadCatalog(*catalogPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading catalog: %v\n", err)
+		os.Exit(1)
+	}
+	names := transformer.SortedServerNames(loaded)
+	if *servers != "" {
+		names = strings.Split(*servers, ",")
+	}
+	var selected []catalog.Server
+	for _, name := range names {
+		server, ok := loaded[strings.TrimSpace(name)]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: no server %q in the catalog\n", name)
+			os.Exit(1)
+		}
+		selected = append(selected, server)
+	}
+
+	result, err := transformer.ExportClientConfig(clientFormat, selected)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	printWarnings(result.Warnings)
+	for _, input := range result.Inputs {
+		kind := "config"
+		if input.Secret {
+			kind = "secret"
+		}
+		fmt.Fprintf(os.Stderr, "Input %s (%s, %s): %s\n", input.ID, kind, input.Env, input.Description)
+	}
+
+	writeOutput(*outputFile, result.JSON, "client config")
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	registryv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	transformer "github.com/slimslenderslacks/catalogs"
+)
+
+func importConfig(args []string) {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	inputFile := flags.String("input", "", "claude_desktop_config.json, .vscode/mcp.json or .cursor/mcp.json (or - for stdin)")
+	outputDir := flags.String("output-dir", "", "Directory for servers/<server>.json and catalog/<server>/server.yaml")
+	flags.Parse(args)
+
+	if *outputDir == "" {
+		fmt.Fprintln(os.Stderr, "Error: -output-dir is required")
+		flags.Usage()
+		os.Exit(1)
+	}
+
+	result, err := transformer.ImportClientConfig([]byte(readInput(*inputFile)))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	for _, imported := range result.Servers {
+		for _, unresolved := range imported.Unresolved {
+			fmt.Fprintf(os.Stderr, "Unresolved %s: %s\n", imported.Key, unresolved)
+		}
+
+		serverJSON, err := json.MarshalIndent(registryv0.ServerResponse{Server: imported.Server}, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error marshaling %s: %v\n", imported.Key, err)
+			os.Exit(1)
+		}
+		writeImported(filepath.Join(*outputDir, "servers", imported.Catalog.Name+".json"), serverJSON)
+
+		entry, err := transformer.MarshalCatalogEntry(*imported.Catalog)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error marshaling catalog entry for %s: %v\n", imported.Key, err)
+			os.Exit(1)
+		}
+		writeImported(filepath.Join(*outputDir, "catalog", imported.Catalog.Name, "server.yaml"), entry)
+	}
+
+	for _, key := range sortedKeys(result.Errors) {
+		fmt.Fprintf(os.Stderr, "Error importing %s: %v\n", key, result.Errors[key])
+	}
+	if len(result.Errors) > 0 {
+		os.Exit(1)
+	}
+}
+
+func writeImported(file string, content []byte) {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating directory %s: %v\n", filepath.Dir(file), err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(file, content, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing file %s: %v\n", file, err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %s\n", file)
+}
@@ -1,59 +1,235 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
 	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	registryv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
 
 	transformer "github.com/slimslenderslacks/catalogs"
 )
 
+// commands are the subcommands; without one the CLI transforms registry JSON.
+var commands = map[string]func(args []string){
+	"admit":           admit,
+	"batch":           batch,
+	"build-context":   buildContexts,
+	"check-overlays":  checkOverlays,
+	"compat":          compat,
+	"deploy":          deploy,
+	"diff":            diff,
+	"discover-oauth":  discoverOAuth,
+	"export":          export,
+	"import":          importConfig,
+	"merge":           merge,
+	"oauth-manifests": oauthManifests,
+	"oauth-register":  oauthRegister,
+	"render":          render,
+	"template":        renderTemplate,
+}
+
 func main() {
-	inputFile := flag.String("input", "", "Input community registry JSON file (or - for stdin)")
-	outputFile := flag.String("output", "", "Output catalog JSON file (or - for stdout)")
-	flag.Parse()
+	if len(os.Args) > 1 {
+		if command, ok := commands[os.Args[1]]; ok {
+			command(os.Args[2:])
+			return
+		}
+	}
+	transform(os.Args[1:])
+}
+
+func transform(args []string) {
+	flags := flag.NewFlagSet("registry-to-catalog", flag.ExitOnError)
+	inputFile := flags.String("input", "", "Input community registry JSON file (or - for stdin)")
+	outputFile := flags.String("output", "", "Output catalog JSON file (or - for stdout)")
+	alternateRemotes := flags.String("alternate-remotes", "", "Write the remotes that were not selected to this JSON file")
+	templateFile := flags.String("template", "", "Render the catalog server with this Go text/template instead of writing JSON")
+	timings := flags.Bool("timings", false, "Print the duration and warnings of each pipeline stage")
+	options := transformOptionFlags(flags)
+	flags.Parse(args)
+
+	opts := options()
+	var tmpl *template.Template
+	if *templateFile != "" {
+		tmpl = loadTemplate(*templateFile)
+	}
 
 	// Read input
-	var inputJSON string
-	var err error
+	inputJSON := readInput(*inputFile)
 
-	if *inputFile == "" || *inputFile == "-" {
-		// Read from stdin
-		bytes, err := io.ReadAll(os.Stdin)
+	// Transform
+	result, err := transformer.TransformJSONWithOptions(inputJSON, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error transforming JSON: %v\n", err)
+		os.Exit(1)
+	}
+	printWarnings(result.Warnings)
+	if result.Egress != nil {
+		for _, line := range result.Egress.Justifications() {
+			fmt.Fprintf(os.Stderr, "Network: %s\n", line)
+		}
+	}
+	printOfficial(result.Official)
+	if *timings {
+		for _, report := range result.Stages {
+			if report.Disabled {
+				fmt.Fprintf(os.Stderr, "Stage %s: disabled\n", report.Stage)
+				continue
+			}
+			fmt.Fprintf(os.Stderr, "Stage %s: %s, %d warnings\n", report.Stage, report.Duration, len(report.Warnings))
+		}
+	}
+	for _, rewrite := range result.Rewrites {
+		fmt.Fprintf(os.Stderr, "Applied %s\n", rewrite)
+	}
+	if result.Overlay != nil {
+		fmt.Fprintf(os.Stderr, "Applied overlay %s\n", result.Overlay.Source)
+	}
+	if ct := result.ContainerTransport; ct != nil {
+		fmt.Fprintf(os.Stderr, "Container transport: %s on port %d (%s)\n", ct.Transport, ct.Port, ct.URL("localhost"))
+	}
+	for _, remote := range result.AlternateRemotes {
+		fmt.Fprintf(os.Stderr, "Alternate remote: %s (%s)\n", remote.URL, remote.Transport)
+	}
+	if *alternateRemotes != "" {
+		alternatesJSON, err := json.MarshalIndent(result.AlternateRemotes, "", "  ")
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error reading from stdin: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Error marshaling alternate remotes: %v\n", err)
+			os.Exit(1)
+		}
+		writeOutput(*alternateRemotes, string(alternatesJSON), "alternate remotes")
+	}
+
+	// Write output
+	if tmpl != nil {
+		writeTemplate(tmpl, *result.Server, *outputFile)
+		return
+	}
+	writeOutput(*outputFile, result.JSON, "catalog")
+}
+
+// transformOptionFlags registers the flags shared by transform and batch.
+// The returned function builds the options once the flags are parsed.
+func transformOptionFlags(flags *flag.FlagSet) func() transformer.TransformOptions {
+	secretScan := flags.String("secret-scan", "warn", "Hardcoded secret detection: off, warn or deny (deny writes no output)")
+	networkPolicy := flags.String("network-policy", "", "Network egress policy YAML applied to image servers")
+	remoteURLPolicy := flags.String("remote-url-policy", "deny", "Unsafe remote URLs: deny, warn or allow-internal (for internal catalogs)")
+	admissionPolicy := flags.String("admission-policy", "", "Admission policy YAML; servers that fail it are not written")
+	transports := flags.String("transports", "streamable-http,sse", "Remote transports in order of preference")
+	status := flags.String("status", "warn", "Deprecated and deleted servers: warn or skip")
+	overlays := flags.String("overlays", "", "Directory of <server-name>.yaml overlays applied after the transform")
+	disableStages := flags.String("disable-stages", "", "Comma-separated transform pipeline stages to skip (e.g. icon,metadata)")
+	rules := flags.String("rules", "", "JSONPath rewrite rules YAML applied to the registry input and catalog output")
+
+	return func() transformer.TransformOptions {
+		var opts transformer.TransformOptions
+		var err error
+		if opts.SecretScan, err = transformer.ParseSecretScanMode(*secretScan); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
-		inputJSON = string(bytes)
-	} else {
-		// Read from file
-		bytes, err := os.ReadFile(*inputFile)
+		if opts.RemoteURLs, err = transformer.ParseRemoteURLMode(*remoteURLPolicy); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if opts.Transports, err = transformer.ParseTransportPreference(*transports); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if opts.Status, err = transformer.ParseStatusMode(*status); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if *networkPolicy != "" {
+			if opts.NetworkPolicy, err = transformer.LoadNetworkPolicy(*networkPolicy); err != nil {
+				fmt.Fprintf(os.Stderr, "Error reading network policy: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if *overlays != "" {
+			if opts.Overlays, err = transformer.LoadOverlays(*overlays); err != nil {
+				fmt.Fprintf(os.Stderr, "Error reading overlays: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if *disableStages != "" {
+			opts.Pipeline = transformer.DefaultPipeline()
+			for _, stage := range strings.Split(*disableStages, ",") {
+				if err := opts.Pipeline.Disable(strings.TrimSpace(stage)); err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
+			}
+		}
+		if *rules != "" {
+			if opts.Rewrites, err = transformer.LoadRewriteRules(*rules); err != nil {
+				fmt.Fprintf(os.Stderr, "Error reading rewrite rules: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if *admissionPolicy != "" {
+			if opts.Admission, err = transformer.LoadAdmissionPolicy(*admissionPolicy); err != nil {
+				fmt.Fprintf(os.Stderr, "Error reading admission policy: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		return opts
+	}
+}
+
+func printOfficial(official *registryv0.RegistryExtensions) {
+	if official == nil {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Registry: %s, published %s, updated %s, latest %t\n", official.Status,
+		official.PublishedAt.Format(time.RFC3339), official.UpdatedAt.Format(time.RFC3339), official.IsLatest)
+}
+
+func printWarnings(warnings []string) {
+	for _, warning := range warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+}
+
+// readInput reads a file, or stdin when the name is empty or "-".
+func readInput(inputFile string) string {
+	if inputFile == "" || inputFile == "-" {
+		// Read from stdin
+		bytes, err := io.ReadAll(os.Stdin)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error reading file %s: %v\n", *inputFile, err)
+			fmt.Fprintf(os.Stderr, "Error reading from stdin: %v\n", err)
 			os.Exit(1)
 		}
-		inputJSON = string(bytes)
+		return string(bytes)
 	}
 
-	// Transform
-	catalogJSON, err := transformer.TransformJSON(inputJSON)
+	// Read from file
+	bytes, err := os.ReadFile(inputFile)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error transforming JSON: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error reading file %s: %v\n", inputFile, err)
 		os.Exit(1)
 	}
+	return string(bytes)
+}
 
-	// Write output
-	if *outputFile == "" || *outputFile == "-" {
+// writeOutput writes content to a file, or stdout when the name is empty or "-".
+func writeOutput(outputFile, content, what string) {
+	if outputFile == "" || outputFile == "-" {
 		// Write to stdout
-		fmt.Println(catalogJSON)
-	} else {
-		// Write to file
-		err := os.WriteFile(*outputFile, []byte(catalogJSON), 0644)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error writing file %s: %v\n", *outputFile, err)
-			os.Exit(1)
-		}
-		fmt.Fprintf(os.Stderr, "Successfully wrote catalog to %s\n", *outputFile)
+		fmt.Println(content)
+		return
+	}
+
+	// Write to file
+	if err := os.WriteFile(outputFile, []byte(content), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing file %s: %v\n", outputFile, err)
+		os.Exit(1)
 	}
+	fmt.Fprintf(os.Stderr, "Successfully wrote %s to %s\n", what, outputFile)
 }
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	transformer "github.com/slimslenderslacks/catalogs"
+	"gopkg.in/yaml.v3"
+)
+
+// mergeConflictExitCode tells conflicts apart from errors, which exit 1, and
+// usage errors, which the flag package exits with 2
+const mergeConflictExitCode = 10
+
+func merge(args []string) {
+	flags := flag.NewFlagSet("merge", flag.ExitOnError)
+	baseFile := flags.String("base", "", "Previously generated entry, or the registry JSON it was generated from")
+	localFile := flags.String("local", "", "Hand-edited entry, e.g. catalog/<server>/server.yaml")
+	upstreamFile := flags.String("upstream", "", "Fresh registry JSON, or an entry generated from it")
+	outputFile := flags.String("output", "", "Output file (or - for stdout)")
+	conflictsFile := flags.String("conflicts", "", "Write conflicts as YAML to this file for review")
+	flags.Parse(args)
+
+	if *baseFile == "" || *localFile == "" || *upstreamFile == "" {
+		fmt.Fprintln(os.Stderr, "Error: -base, -local and -upstream are required")
+		flags.Usage()
+		os.Exit(1)
+	}
+
+	result, err := transformer.MergeCatalogEntry(
+		[]byte(readInput(*baseFile)),
+		[]byte(readInput(*localFile)),
+		[]byte(readInput(*upstreamFile)),
+	)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error merging: %v\n", err)
+		os.Exit(1)
+	}
+
+	writeOutput(*outputFile, strings.TrimRight(string(result.Document), "\n"), "merged entry")
+
+	if len(result.Conflicts) == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "%d conflicts, local values kept:\n%s\n", len(result.Conflicts), transformer.FormatMergeConflicts(result.Conflicts))
+	if *conflictsFile != "" {
+		data, err := yaml.Marshal(result.Conflicts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error marshaling conflicts: %v\n", err)
+			os.Exit(1)
+		}
+		writeOutput(*conflictsFile, string(data), "conflicts")
+	}
+	os.Exit(mergeConflictExitCode)
+}
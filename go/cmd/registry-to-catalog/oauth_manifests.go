@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	transformer "github.com/slimslenderslacks/catalogs"
+)
+
+func oauthManifests(args []string) {
+	flags := flag.NewFlagSet("oauth-manifests", flag.ExitOnError)
+	catalogPath := flags.String("catalog", "", "Catalog file or catalog/ directory")
+	outputDir := flags.String("output-dir", "", "Directory for <server>/oauth.yaml manifests (default: the catalog directory)")
+	timeout := flags.Duration("timeout", 30*time.Second, "HTTP timeout per server")
+	flags.Parse(args)
+
+	if *catalogPath == "" {
+		fmt.Fprintln(os.Stderr, "Error: -catalog is required")
+		flags.Usage()
+		os.Exit(1)
+	}
+	if *outputDir == "" {
+		if info, err := os.Stat(*catalogPath); err == nil && info.IsDir() {
+			*outputDir = *catalogPath
+		} else {
+			fmt.Fprintln(os.Stderr, "Error: -output-dir is required when -catalog is a file")
+			os.Exit(1)
+		}
+	}
+
+	servers, err := transformer.LoadCatalog(*catalogPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading catalog: %v\n", err)
+		os.Exit(1)
+	}
+
+	registrations, errs := transformer.BuildOAuthRegistrations(context.Background(), http.DefaultClient, servers, *timeout)
+	for _, registration := range registrations {
+		manifest, err := transformer.MarshalOAuthRegistration(registration)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error marshaling manifest for %s: %v\n", registration.Server, err)
+			os.Exit(1)
+		}
+
+		dir := filepath.Join(*outputDir, registration.Server)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating directory %s: %v\n", dir, err)
+			os.Exit(1)
+		}
+		file := filepath.Join(dir, transformer.OAuthManifestFilename)
+		if err := os.WriteFile(file, manifest, 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing file %s: %v\n", file, err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "Successfully wrote OAuth manifest to %s\n", file)
+	}
+
+	for _, name := range sortedKeys(errs) {
+		fmt.Fprintf(os.Stderr, "Error building OAuth manifest for %s: %v\n", name, errs[name])
+	}
+	if len(errs) > 0 {
+		os.Exit(1)
+	}
+}
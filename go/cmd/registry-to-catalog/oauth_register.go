@@ -0,0 +1,34 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	transformer "github.com/slimslenderslacks/catalogs"
+)
+
+// oauthRegister prints the docker mcp oauth register command for an OAuth
+// registration manifest
+func oauthRegister(args []string) {
+	flags := flag.NewFlagSet("oauth-register", flag.ExitOnError)
+	manifest := flags.String("manifest", "", "OAuth registration manifest, e.g. catalog/<server>/oauth.yaml")
+	flags.Parse(args)
+
+	if *manifest == "" {
+		fmt.Fprintln(os.Stderr, "Error: -manifest is required")
+		flags.Usage()
+		os.Exit(1)
+	}
+	registration, err := transformer.LoadOAuthRegistration(*manifest)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	command, err := transformer.OAuthRegisterCommand(*registration)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println(command)
+}
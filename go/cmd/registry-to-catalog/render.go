@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	transformer "github.com/slimslenderslacks/catalogs"
+	"gopkg.in/yaml.v3"
+)
+
+// render prints the docker run invocation the gateway would use, without
+// running it
+func render(args []string) {
+	flags := flag.NewFlagSet("render", flag.ExitOnError)
+	catalogPath := flags.String("catalog", "", "Catalog file, catalog server file or catalog/ directory")
+	serverName := flags.String("server", "", "Server to render (required when the catalog has several)")
+	configFile := flags.String("config", "", "Config values YAML, as in the gateway's config.yaml")
+	secretsFile := flags.String("secrets", "", "YAML mapping of secret names to references")
+	readOnly := flags.Bool("read-only", false, "Mount volumes read-only, as for read-only clients")
+	jsonOutput := flags.Bool("json", false, "Print argv and env as JSON")
+	opts := transformer.RenderOptions{Secrets: make(map[string]string)}
+	flags.Func("secret", "Secret reference as name=reference (repeatable)", func(s string) error {
+		name, reference, ok := strings.Cut(s, "=")
+		if !ok {
+			return fmt.Errorf("expected name=reference")
+		}
+		opts.Secrets[name] = reference
+		return nil
+	})
+	flags.Func("network", "Network the gateway attaches servers to (repeatable)", func(s string) error {
+		opts.Networks = append(opts.Networks, s)
+		return nil
+	})
+	flags.IntVar(&opts.Cpus, "cpus", 1, "CPUs allocated to the server")
+	flags.StringVar(&opts.Memory, "memory", "2Gb", "Memory allocated to the server")
+	flags.Parse(args)
+
+	if *catalogPath == "" {
+		fmt.Fprintln(os.Stderr, "Error: -catalog is required")
+		flags.Usage()
+		os.Exit(1)
+	}
+	opts.ReadOnly = *readOnly
+	if *configFile != "" {
+		readYAML(*configFile, &opts.Config, "config")
+	}
+	if *secretsFile != "" {
+		var secrets map[string]string
+		readYAML(*secretsFile, &secrets, "secrets")
+		for name, reference := range secrets {
+			if _, ok := opts.Secrets[name]; !ok {
+				opts.Secrets[name] = reference
+			}
+		}
+	}
+
+	servers, err := transformer.LoadCatalog(*catalogPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading catalog: %v\n", err)
+		os.Exit(1)
+	}
+	var server catalog.Server
+	switch {
+	case *serverName != "":
+		var ok bool
+		if server, ok = servers[*serverName]; !ok {
+			fmt.Fprintf(os.Stderr, "Error: no server %q in the catalog\n", *serverName)
+			os.Exit(1)
+		}
+	case len(servers) == 1:
+		for _, only := range servers {
+			server = only
+		}
+	default:
+		fmt.Fprintln(os.Stderr, "Error: -server is required when the catalog has several servers")
+		os.Exit(1)
+	}
+
+	run, err := transformer.RenderDockerRun(server, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *jsonOutput {
+		data, err := json.MarshalIndent(struct {
+			Argv []string `json:"argv"`
+			Env  []string `json:"env"`
+		}{run.Argv, run.Env}, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error marshaling invocation: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+		return
+	}
+	fmt.Println(run)
+}
+
+func readYAML(file string, v any, what string) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading %s file %s: %v\n", what, file, err)
+		os.Exit(1)
+	}
+	if err := yaml.Unmarshal(data, v); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing %s file %s: %v\n", what, file, err)
+		os.Exit(1)
+	}
+}
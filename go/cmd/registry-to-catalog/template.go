@@ -0,0 +1,53 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	transformer "github.com/slimslenderslacks/catalogs"
+)
+
+func renderTemplate(args []string) {
+	flags := flag.NewFlagSet("template", flag.ExitOnError)
+	catalogPath := flags.String("catalog", "", "Catalog file or catalog/ directory")
+	templateFile := flags.String("template", "", "Go text/template rendered over the whole catalog")
+	outputFile := flags.String("output", "", "Output file (or - for stdout)")
+	flags.Parse(args)
+
+	if *catalogPath == "" || *templateFile == "" {
+		fmt.Fprintln(os.Stderr, "Error: -catalog and -template are required")
+		flags.Usage()
+		os.Exit(1)
+	}
+
+	tmpl := loadTemplate(*templateFile)
+	servers, err := transformer.LoadCatalog(*catalogPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading catalog: %v\n", err)
+		os.Exit(1)
+	}
+
+	writeTemplate(tmpl, transformer.NewTemplateCatalog("", "", servers), *outputFile)
+}
+
+func loadTemplate(file string) *template.Template {
+	tmpl, err := transformer.LoadOutputTemplate(file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading template: %v\n", err)
+		os.Exit(1)
+	}
+	return tmpl
+}
+
+// writeTemplate renders a catalog.Server or a TemplateCatalog to a file or stdout
+func writeTemplate(tmpl *template.Template, data any, outputFile string) {
+	content, err := transformer.RenderTemplate(tmpl, data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	writeOutput(outputFile, strings.TrimRight(content, "\n"), "output")
+}
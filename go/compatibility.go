@@ -0,0 +1,221 @@
+package catalogs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"gopkg.in/yaml.v3"
+)
+
+// Compatibility classifies a change for users of a catalog entry
+type Compatibility string
+
+const (
+	// Cosmetic changes only affect how a server is presented
+	Cosmetic Compatibility = "cosmetic"
+	// Additive changes keep existing configurations working
+	Additive Compatibility = "additive"
+	// Breaking changes need users to act, e.g. provide a new secret
+	Breaking Compatibility = "breaking"
+)
+
+// compatibilityRank orders levels so the most severe can be picked
+var compatibilityRank = map[Compatibility]int{"": 0, Cosmetic: 1, Additive: 2, Breaking: 3}
+
+// ExitCode is the CLI exit status for a level: 0 for none or cosmetic, 10
+// for additive and 11 for breaking. 1 is left for errors and 2 for usage
+// errors, which the flag package exits with.
+func (c Compatibility) ExitCode() int {
+	switch c {
+	case Additive:
+		return 10
+	case Breaking:
+		return 11
+	}
+	return 0
+}
+
+// AtLeast reports whether c is as severe as other
+func (c Compatibility) AtLeast(other Compatibility) bool {
+	return compatibilityRank[c] >= compatibilityRank[other]
+}
+
+// ParseCompatibility parses a -fail-on flag value
+func ParseCompatibility(s string) (Compatibility, error) {
+	switch c := Compatibility(s); c {
+	case Cosmetic, Additive, Breaking:
+		return c, nil
+	}
+	return "", fmt.Errorf("invalid compatibility level %q (expected cosmetic, additive or breaking)", s)
+}
+
+// ClassifiedChange is a field change with its compatibility
+type ClassifiedChange struct {
+	FieldChange
+	Compatibility Compatibility `json:"compatibility"`
+	Reason        string        `json:"reason"`
+}
+
+func (c ClassifiedChange) String() string {
+	return fmt.Sprintf("[%s] %s (%s)", c.Compatibility, c.FieldChange, c.Reason)
+}
+
+// CompatibilityReport is the outcome of AnalyzeCompatibility
+type CompatibilityReport struct {
+	Server  string             `json:"server"`
+	Changes []ClassifiedChange `json:"changes"`
+	// Level is the most severe classification, empty when nothing changed
+	Level Compatibility `json:"level,omitempty"`
+}
+
+// compatibilityRule classifies the changes to one field. The first rule
+// whose field matches is used.
+type compatibilityRule struct {
+	field    string
+	classify func(change FieldChange) (Compatibility, string)
+}
+
+func always(c Compatibility, reason string) func(FieldChange) (Compatibility, string) {
+	return func(FieldChange) (Compatibility, string) { return c, reason }
+}
+
+var compatibilityRules = []compatibilityRule{
+	{"secrets", func(c FieldChange) (Compatibility, string) {
+		switch c.Kind {
+		case ChangeAdded:
+			return Breaking, "users must provide a new secret"
+		case ChangeChanged:
+			// Client configs exported with the old name stop passing it
+			return Breaking, fmt.Sprintf("the secret moved from env %s to %s", c.Old, c.New)
+		}
+		return Additive, "the secret is no longer used"
+	}},
+	{"config", func(c FieldChange) (Compatibility, string) {
+		switch {
+		case c.Kind == ChangeAdded && c.Required:
+			return Breaking, "users must provide new required config"
+		case c.Kind == ChangeAdded:
+			return Additive, "new optional config"
+		case strings.HasSuffix(c.Field, ".required") && c.New == "true":
+			return Breaking, "config became required"
+		case strings.HasSuffix(c.Field, ".required"):
+			return Additive, "config became optional"
+		case c.Kind == ChangeRemoved:
+			return Additive, "the config value is no longer used"
+		}
+		return Breaking, "the config schema changed"
+	}},
+	{"env", func(c FieldChange) (Compatibility, string) {
+		switch c.Kind {
+		case ChangeRemoved:
+			return Breaking, "an environment variable was removed"
+		case ChangeAdded:
+			return Additive, "new environment variable"
+		}
+		return Additive, "an environment value changed"
+	}},
+	{"type", always(Breaking, "the server type changed")},
+	{"remote.transport_type", always(Breaking, "the transport changed")},
+	{"remote.url", func(c FieldChange) (Compatibility, string) {
+		if c.Kind == ChangeChanged {
+			return Breaking, "the remote endpoint moved"
+		}
+		return Breaking, "the transport changed"
+	}},
+	{"sseEndpoint", always(Breaking, "the transport changed")},
+	{"remote.headers", func(c FieldChange) (Compatibility, string) {
+		if c.Kind == ChangeRemoved {
+			return Additive, "a header is no longer sent"
+		}
+		return Breaking, "the remote expects different headers"
+	}},
+	{"oauth.providers", func(c FieldChange) (Compatibility, string) {
+		if c.Kind == ChangeRemoved {
+			return Additive, "an OAuth provider is no longer needed"
+		}
+		return Breaking, "users must authorize with a new OAuth provider"
+	}},
+	{"oauth.scopes", always(Breaking, "users must consent to different scopes")},
+	{"image", func(c FieldChange) (Compatibility, string) {
+		if c.Kind == ChangeDigestBump {
+			return Additive, "new image build"
+		}
+		if c.Kind == ChangeChanged && parseImageReference(c.Old).Repository == parseImageReference(c.New).Repository {
+			return Additive, "new image tag"
+		}
+		return Breaking, "the image changed"
+	}},
+	{"volumes", always(Breaking, "mounted volumes changed")},
+	{"user", always(Breaking, "the container user changed")},
+	{"disableNetwork", func(c FieldChange) (Compatibility, string) {
+		if c.New == "true" {
+			return Breaking, "network access was disabled"
+		}
+		return Additive, "network access was enabled"
+	}},
+	{"prefix", always(Breaking, "tool names changed")},
+	{"tools", always(Additive, "the tool list changed")},
+	{"command", always(Additive, "the container command changed")},
+	{"allowHosts", always(Additive, "allowed hosts changed")},
+	{"extraHosts", always(Additive, "extra hosts changed")},
+	{"longLived", always(Additive, "the container lifetime changed")},
+}
+
+// classifyChange applies the first matching rule; fields without a rule,
+// such as title, description, icon, readme and metadata, are cosmetic
+func classifyChange(change FieldChange) ClassifiedChange {
+	for _, rule := range compatibilityRules {
+		if change.Field == rule.field || strings.HasPrefix(change.Field, rule.field+".") {
+			compatibility, reason := rule.classify(change)
+			return ClassifiedChange{FieldChange: change, Compatibility: compatibility, Reason: reason}
+		}
+	}
+	return ClassifiedChange{FieldChange: change, Compatibility: Cosmetic, Reason: "presentation only"}
+}
+
+// AnalyzeCompatibility classifies the changes between two successive
+// TransformToDocker outputs for the same server
+func AnalyzeCompatibility(old, new catalog.Server) CompatibilityReport {
+	report := CompatibilityReport{Server: new.Name}
+	for _, change := range DiffServers(old, new) {
+		classified := classifyChange(change)
+		report.Changes = append(report.Changes, classified)
+		if !report.Level.AtLeast(classified.Compatibility) {
+			report.Level = classified.Compatibility
+		}
+	}
+	return report
+}
+
+// ParseServerVersion reads one version of a server, either registry JSON
+// (which is transformed) or a single catalog server in JSON or YAML
+func ParseServerVersion(data []byte) (catalog.Server, error) {
+	var probe map[string]any
+	if err := yaml.Unmarshal(data, &probe); err != nil {
+		return catalog.Server{}, err
+	}
+	if _, ok := probe["server"]; ok {
+		response, err := parseServerResponse(data)
+		if err != nil {
+			return catalog.Server{}, err
+		}
+		server, err := TransformToDocker(response.Server)
+		if err != nil {
+			return catalog.Server{}, err
+		}
+		return *server, nil
+	}
+
+	servers, err := ParseCatalog(data)
+	if err != nil {
+		return catalog.Server{}, err
+	}
+	if len(servers) != 1 {
+		return catalog.Server{}, fmt.Errorf("expected one server, found %d", len(servers))
+	}
+	for _, server := range servers {
+		return server, nil
+	}
+	return catalog.Server{}, nil
+}
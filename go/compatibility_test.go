@@ -0,0 +1,107 @@
+package catalogs
+
+import (
+	"testing"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+func TestAnalyzeCompatibility(t *testing.T) {
+	old := catalog.Server{
+		Name:   "com-example-weather",
+		Type:   "remote",
+		Title:  "Weather",
+		Remote: catalog.Remote{URL: "https://example.com/sse", Transport: "sse"},
+		Env:    []catalog.Env{{Name: "UNITS", Value: "metric"}},
+	}
+
+	tests := []struct {
+		name   string
+		change func(s *catalog.Server)
+		want   Compatibility
+	}{
+		{"no change", func(s *catalog.Server) {}, ""},
+		{"title", func(s *catalog.Server) { s.Title = "Weather Forecasts" }, Cosmetic},
+		{"new env", func(s *catalog.Server) { s.Env = append(s.Env, catalog.Env{Name: "DEBUG", Value: "false"}) }, Additive},
+		{"removed env", func(s *catalog.Server) { s.Env = nil }, Breaking},
+		{"transport", func(s *catalog.Server) {
+			s.Remote = catalog.Remote{URL: "https://example.com/mcp", Transport: "streamable-http"}
+		}, Breaking},
+		{"new secret", func(s *catalog.Server) {
+			s.Secrets = []catalog.Secret{{Name: "com-example-weather.api_key", Env: "API_KEY"}}
+		}, Breaking},
+		{"optional config", func(s *catalog.Server) {
+			s.Config = []any{map[string]any{"properties": map[string]any{"city": map[string]any{"type": "string"}}}}
+		}, Additive},
+		{"required config", func(s *catalog.Server) {
+			s.Config = []any{map[string]any{"properties": map[string]any{"city": map[string]any{"type": "string"}}, "required": []any{"city"}}}
+		}, Breaking},
+	}
+
+	for _, tt := range tests {
+		new := old
+		new.Env = append([]catalog.Env{}, old.Env...)
+		tt.change(&new)
+		report := AnalyzeCompatibility(old, new)
+		if report.Level != tt.want {
+			t.Errorf("%s: expected %q, got %q (%v)", tt.name, tt.want, report.Level, report.Changes)
+		}
+	}
+}
+
+func TestAnalyzeCompatibilitySecretEnv(t *testing.T) {
+	old := catalog.Server{
+		Name:    "com-example-weather",
+		Type:    "server",
+		Secrets: []catalog.Secret{{Name: "com-example-weather.api_key", Env: "API_KEY"}},
+	}
+	new := old
+	new.Secrets = []catalog.Secret{{Name: "com-example-weather.api_key", Env: "WEATHER_API_KEY"}}
+
+	report := AnalyzeCompatibility(old, new)
+	if report.Level != Breaking || len(report.Changes) != 1 {
+		t.Fatalf("Expected one breaking change, got %v", report.Changes)
+	}
+	if reason := report.Changes[0].Reason; reason != "the secret moved from env API_KEY to WEATHER_API_KEY" {
+		t.Errorf("Unexpected reason %q", reason)
+	}
+}
+
+func TestAnalyzeCompatibilityImage(t *testing.T) {
+	old := catalog.Server{Name: "com-example-notes", Type: "server", Image: "docker.io/example/notes@sha256:1111111111111111111111111111111111111111111111111111111111111111"}
+
+	new := old
+	new.Image = "docker.io/example/notes@sha256:2222222222222222222222222222222222222222222222222222222222222222"
+	if report := AnalyzeCompatibility(old, new); report.Level != Additive {
+		t.Errorf("Expected a digest bump to be additive, got %+v", report)
+	}
+
+	new.Image = "ghcr.io/someone-else/notes:1.0.0"
+	if report := AnalyzeCompatibility(old, new); report.Level != Breaking {
+		t.Errorf("Expected a different image to be breaking, got %+v", report)
+	}
+}
+
+func TestCompatibilityExitCode(t *testing.T) {
+	if Compatibility("").ExitCode() != 0 || Cosmetic.ExitCode() != 0 || Additive.ExitCode() != 10 || Breaking.ExitCode() != 11 {
+		t.Error("Unexpected exit codes")
+	}
+	if !Breaking.AtLeast(Additive) || Cosmetic.AtLeast(Additive) {
+		t.Error("Unexpected ordering")
+	}
+}
+
+func TestParseServerVersion(t *testing.T) {
+	server, err := ParseServerVersion([]byte(remoteRegistryJSON("https://grafana.example.com/mcp")))
+	if err != nil {
+		t.Fatalf("ParseServerVersion failed: %v", err)
+	}
+	if server.Name != "com-docker-grafana-internal" || server.Remote.URL != "https://grafana.example.com/mcp" {
+		t.Errorf("Unexpected server %+v", server)
+	}
+
+	server, err = ParseServerVersion([]byte(`{"name": "com-example-notes", "type": "server", "image": "example/notes"}`))
+	if err != nil || server.Image != "example/notes" {
+		t.Errorf("Expected a catalog server, got %+v %v", server, err)
+	}
+}
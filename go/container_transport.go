@@ -0,0 +1,111 @@
+package catalogs
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// ContainerTransport describes how to reach an OCI package that serves MCP
+// over HTTP instead of stdio: the container is started and the client
+// connects to Port and Path inside it
+type ContainerTransport struct {
+	Transport string `json:"transport" yaml:"transport"`
+	Port      int    `json:"port" yaml:"port"`
+	Path      string `json:"path,omitempty" yaml:"path,omitempty"`
+}
+
+// URL is the endpoint on the given host, e.g. localhost or the container name
+func (t ContainerTransport) URL(host string) string {
+	return fmt.Sprintf("http://%s:%d%s", host, t.Port, t.Path)
+}
+
+// isHTTPTransport reports whether a package transport is served over HTTP
+func isHTTPTransport(transport model.Transport) bool {
+	return transport.Type == model.TransportTypeStreamableHTTP || transport.Type == model.TransportTypeSSE
+}
+
+// packageValue resolves a {name} reference in a package transport URL. It may
+// name a transport variable, a package argument (by name or value hint) or an
+// environment variable; their default or literal value is used.
+func packageValue(pkg model.Package, name string) (string, bool) {
+	first := func(values ...string) (string, bool) {
+		for _, value := range values {
+			if value != "" && !urlVariablePattern.MatchString(value) {
+				return value, true
+			}
+		}
+		return "", false
+	}
+
+	if input, ok := pkg.Transport.Variables[name]; ok {
+		return first(input.Default, input.Value)
+	}
+	for _, arg := range append(append([]model.Argument{}, pkg.PackageArguments...), pkg.RuntimeArguments...) {
+		if input, ok := arg.Variables[name]; ok {
+			return first(input.Default, input.Value)
+		}
+		if strings.TrimLeft(arg.Name, "-") == strings.TrimLeft(name, "-") || arg.ValueHint == name {
+			return first(arg.Default, arg.Value)
+		}
+	}
+	for _, env := range pkg.EnvironmentVariables {
+		if input, ok := env.Variables[name]; ok {
+			return first(input.Default, input.Value)
+		}
+		if strings.EqualFold(env.Name, name) {
+			return first(env.Default, env.Value)
+		}
+	}
+	return "", false
+}
+
+// containerTransport maps the transport URL of an OCI package served over
+// streamable-http or sse to the container port and path. It returns nil for
+// stdio packages.
+func containerTransport(pkg model.Package) (*ContainerTransport, error) {
+	if pkg.RegistryType != model.RegistryTypeOCI || !isHTTPTransport(pkg.Transport) {
+		return nil, nil
+	}
+	if pkg.Transport.URL == "" {
+		return nil, fmt.Errorf("package %s uses %s but declares no transport URL", pkg.Identifier, pkg.Transport.Type)
+	}
+
+	var unresolved []string
+	rendered := urlVariablePattern.ReplaceAllStringFunc(pkg.Transport.URL, func(match string) string {
+		name := match[1 : len(match)-1]
+		value, ok := packageValue(pkg, name)
+		if !ok {
+			unresolved = append(unresolved, name)
+			return match
+		}
+		return value
+	})
+	if len(unresolved) > 0 {
+		return nil, fmt.Errorf("package %s transport URL %s has no default for: %s", pkg.Identifier, pkg.Transport.URL, strings.Join(unresolved, ", "))
+	}
+
+	u, err := url.Parse(rendered)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("package %s has an invalid transport URL %s", pkg.Identifier, pkg.Transport.URL)
+	}
+
+	port := 80
+	if u.Scheme == "https" {
+		port = 443
+	}
+	if p := u.Port(); p != "" {
+		if port, err = strconv.Atoi(p); err != nil || port <= 0 || port > 65535 {
+			return nil, fmt.Errorf("package %s transport URL %s has an invalid port %q", pkg.Identifier, pkg.Transport.URL, p)
+		}
+	}
+
+	return &ContainerTransport{
+		Transport: pkg.Transport.Type,
+		Port:      port,
+		Path:      u.Path,
+	}, nil
+}
@@ -0,0 +1,141 @@
+package catalogs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+func TestTransformOCIStreamableHTTP(t *testing.T) {
+	registryJSON := `{
+		"server": {
+			"name": "io.github.example/http-server",
+			"description": "Containerized HTTP server",
+			"version": "1.2.0",
+			"packages": [
+				{
+					"registryType": "oci",
+					"identifier": "ghcr.io/example/http-server",
+					"version": "1.2.0",
+					"transport": {"type": "streamable-http", "url": "http://localhost:{port}/mcp"},
+					"packageArguments": [
+						{"type": "named", "name": "--port", "value": "{port}", "variables": {"port": {"default": "8080"}}}
+					]
+				}
+			]
+		}
+	}`
+
+	result, err := TransformJSONWithOptions(registryJSON, TransformOptions{})
+	if err != nil {
+		t.Fatalf("TransformJSONWithOptions failed: %v", err)
+	}
+
+	if result.Server.Type != "server" || result.Server.Image != "ghcr.io/example/http-server:1.2.0" {
+		t.Errorf("Expected image server, got type %s image %s", result.Server.Type, result.Server.Image)
+	}
+	if !result.Server.LongLived {
+		t.Error("Expected HTTP container to be long lived")
+	}
+	expected := ContainerTransport{Transport: "streamable-http", Port: 8080, Path: "/mcp"}
+	if result.ContainerTransport == nil || *result.ContainerTransport != expected {
+		t.Errorf("Expected %+v, got %+v", expected, result.ContainerTransport)
+	}
+	if url := result.ContainerTransport.URL("localhost"); url != "http://localhost:8080/mcp" {
+		t.Errorf("Unexpected URL %s", url)
+	}
+
+	// The gateway only talks stdio to images, so the entry is flagged and
+	// the port is kept in its metadata
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "over stdio") {
+		t.Errorf("Expected a stdio warning, got %v", result.Warnings)
+	}
+	file := filepath.Join(t.TempDir(), "server.json")
+	if err := os.WriteFile(file, []byte(result.JSON), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	metadata, err := LoadCatalogMetadata(file)
+	if err != nil {
+		t.Fatalf("LoadCatalogMetadata failed: %v", err)
+	}
+	if got := metadata["io-github-example-http-server"].ContainerTransport; got == nil || *got != expected {
+		t.Errorf("Expected %+v in the catalog metadata, got %+v", expected, got)
+	}
+}
+
+func TestContainerTransport(t *testing.T) {
+	tests := []struct {
+		name string
+		pkg  model.Package
+		want ContainerTransport
+	}{
+		{
+			name: "literal port",
+			pkg: model.Package{
+				RegistryType: "oci",
+				Transport:    model.Transport{Type: "sse", URL: "http://localhost:3000/sse"},
+			},
+			want: ContainerTransport{Transport: "sse", Port: 3000, Path: "/sse"},
+		},
+		{
+			name: "port from environment variable",
+			pkg: model.Package{
+				RegistryType: "oci",
+				Transport:    model.Transport{Type: "streamable-http", URL: "http://localhost:{PORT}/mcp"},
+				EnvironmentVariables: []model.KeyValueInput{
+					{Name: "PORT", InputWithVariables: model.InputWithVariables{Input: model.Input{Default: "9000"}}},
+				},
+			},
+			want: ContainerTransport{Transport: "streamable-http", Port: 9000, Path: "/mcp"},
+		},
+		{
+			name: "port from argument value hint",
+			pkg: model.Package{
+				RegistryType: "oci",
+				Transport:    model.Transport{Type: "streamable-http", URL: "http://localhost:{port}"},
+				PackageArguments: []model.Argument{
+					{Type: "named", Name: "--listen-port", ValueHint: "port", InputWithVariables: model.InputWithVariables{Input: model.Input{Default: "7000"}}},
+				},
+			},
+			want: ContainerTransport{Transport: "streamable-http", Port: 7000},
+		},
+		{
+			name: "default port",
+			pkg: model.Package{
+				RegistryType: "oci",
+				Transport:    model.Transport{Type: "streamable-http", URL: "http://localhost/mcp"},
+			},
+			want: ContainerTransport{Transport: "streamable-http", Port: 80, Path: "/mcp"},
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := containerTransport(tt.pkg)
+		if err != nil {
+			t.Errorf("%s: containerTransport failed: %v", tt.name, err)
+			continue
+		}
+		if got == nil || *got != tt.want {
+			t.Errorf("%s: expected %+v, got %+v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestContainerTransportErrors(t *testing.T) {
+	stdio, err := containerTransport(model.Package{RegistryType: "oci", Transport: model.Transport{Type: "stdio"}})
+	if err != nil || stdio != nil {
+		t.Errorf("Expected stdio packages to be ignored, got %+v %v", stdio, err)
+	}
+
+	_, err = containerTransport(model.Package{
+		RegistryType: "oci",
+		Identifier:   "example/server",
+		Transport:    model.Transport{Type: "streamable-http", URL: "http://localhost:{port}/mcp"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "no default for: port") {
+		t.Errorf("Expected unresolved port error, got %v", err)
+	}
+}
@@ -0,0 +1,526 @@
+package catalogs
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+// DeployTarget is a runtime the deploy generators write manifests for
+type DeployTarget string
+
+const (
+	// DeployCompose is a compose.yaml with one service per server
+	DeployCompose DeployTarget = "compose"
+	// DeployKubernetes is a list of Deployment, Service, ExternalSecret and
+	// NetworkPolicy manifests
+	DeployKubernetes DeployTarget = "kubernetes"
+)
+
+// ParseDeployTarget parses a -target flag value
+func ParseDeployTarget(s string) (DeployTarget, error) {
+	switch target := DeployTarget(s); target {
+	case DeployCompose, DeployKubernetes:
+		return target, nil
+	}
+	return "", fmt.Errorf("invalid deploy target %q (expected compose or kubernetes)", s)
+}
+
+// DeployOptions are the values servers are deployed with
+type DeployOptions struct {
+	// Config holds config values keyed by server name, as in the gateway's
+	// config.yaml and RenderOptions.Config
+	Config map[string]any
+	// Ports are the container ports of servers that serve MCP over HTTP,
+	// keyed by server name. Servers without a port are deployed as stdio.
+	Ports map[string]int
+	// Namespace of the Kubernetes manifests (default: none)
+	Namespace string
+	// SecretStore is the external-secrets store secrets are read from
+	// (default mcp-secrets)
+	SecretStore string
+	// SecretStoreKind is SecretStore or ClusterSecretStore (default)
+	SecretStoreKind string
+}
+
+// DeployResult is a generated compose file or Kubernetes manifest list
+type DeployResult struct {
+	YAML string
+	// Secrets are the external references the manifests expect, e.g. the
+	// compose environment variable or the store key
+	Secrets  []DeploySecret
+	Warnings []string
+}
+
+// DeploySecret is a secret the deployment reads from outside the manifests
+type DeploySecret struct {
+	Server string
+	// Name is the catalog secret name
+	Name string
+	// Env is the variable the server reads it from
+	Env string
+	// Reference is the compose variable or the external store key
+	Reference string
+}
+
+// secretMarker stands in for a secret reference while values are resolved,
+// so literal $ in config values can be escaped for the target afterwards
+var secretMarker = regexp.MustCompile("\x00([A-Za-z0-9_]+)\x00")
+
+// dnsLabelPattern matches what is not allowed in Kubernetes names and compose
+// service names
+var dnsLabelPattern = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// deployName turns a server name into a DNS label, e.g. com.example/notes
+// into com-example-notes
+func deployName(name string) string {
+	label := strings.Trim(dnsLabelPattern.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if len(label) > 63 {
+		label = strings.TrimRight(label[:63], "-")
+	}
+	return label
+}
+
+// deployable resolves the containers of the image servers, skipping remotes
+// and servers without an image with a warning. Secret references are left
+// as markers around the name returned by reference.
+func deployable(servers []catalog.Server, opts DeployOptions, reference func(catalog.Secret) string, result *DeployResult) ([]catalog.Server, []containerSpec, error) {
+	var selected []catalog.Server
+	var specs []containerSpec
+	seen := make(map[string]string)
+	for _, server := range servers {
+		if server.Remote.URL != "" || server.SSEEndpoint != "" {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s is a remote, skipped", server.Name))
+			continue
+		}
+		if server.Image == "" {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s has no image, skipped", server.Name))
+			continue
+		}
+		name := deployName(server.Name)
+		if other, ok := seen[name]; ok {
+			return nil, nil, fmt.Errorf("servers %s and %s both deploy as %s", other, server.Name, name)
+		}
+		seen[name] = server.Name
+
+		spec, err := resolveContainer(server, opts.Config, func(secret catalog.Secret) string {
+			ref := reference(secret)
+			result.Secrets = append(result.Secrets, DeploySecret{Server: server.Name, Name: secret.Name, Env: secret.Env, Reference: ref})
+			return "\x00" + secret.Env + "\x00"
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(server.AllowHosts) > 0 {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s only allows egress to %s; hosts cannot be enforced by the generated manifests", server.Name, strings.Join(server.AllowHosts, ", ")))
+		}
+		selected = append(selected, server)
+		specs = append(specs, spec)
+	}
+	return selected, specs, nil
+}
+
+// parseVolume splits a src:dst[:ro] volume
+func parseVolume(volume string) (source, target string, readOnly bool, err error) {
+	parts := strings.Split(volume, ":")
+	if len(parts) > 1 && (parts[len(parts)-1] == "ro" || parts[len(parts)-1] == "rw") {
+		readOnly = parts[len(parts)-1] == "ro"
+		parts = parts[:len(parts)-1]
+	}
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false, fmt.Errorf("invalid volume %q (expected source:target)", volume)
+	}
+	return parts[0], parts[1], readOnly, nil
+}
+
+// isNamedVolume reports whether a volume source is a volume name rather than
+// a host path
+func isNamedVolume(source string) bool {
+	return !strings.HasPrefix(source, "/") && !strings.HasPrefix(source, ".") && !strings.HasPrefix(source, "~")
+}
+
+type composeFile struct {
+	Services map[string]composeService `yaml:"services"`
+	Volumes  map[string]map[string]any `yaml:"volumes,omitempty"`
+}
+
+type composeService struct {
+	Image       string            `yaml:"image"`
+	Command     []string          `yaml:"command,omitempty"`
+	Environment map[string]string `yaml:"environment,omitempty"`
+	Volumes     []string          `yaml:"volumes,omitempty"`
+	User        string            `yaml:"user,omitempty"`
+	NetworkMode string            `yaml:"network_mode,omitempty"`
+	ExtraHosts  []string          `yaml:"extra_hosts,omitempty"`
+	Expose      []string          `yaml:"expose,omitempty"`
+	StdinOpen   bool              `yaml:"stdin_open,omitempty"`
+	Init        bool              `yaml:"init"`
+	SecurityOpt []string          `yaml:"security_opt"`
+	Labels      map[string]string `yaml:"labels"`
+}
+
+// GenerateCompose writes a compose.yaml with a service per image server.
+// Config is resolved from opts.Config; secrets are read from environment
+// variables named after the secret (e.g. ${COM_EXAMPLE_NOTES_API_KEY}), which
+// compose takes from the shell or an .env file.
+func GenerateCompose(servers []catalog.Server, opts DeployOptions) (*DeployResult, error) {
+	result := &DeployResult{}
+	selected, specs, err := deployable(servers, opts, func(secret catalog.Secret) string {
+		return envName(secret.Name)
+	}, result)
+	if err != nil {
+		return nil, err
+	}
+	references := make(map[string]map[string]string)
+	for _, secret := range result.Secrets {
+		if references[secret.Server] == nil {
+			references[secret.Server] = make(map[string]string)
+		}
+		references[secret.Server][secret.Env] = secret.Reference
+	}
+
+	doc := composeFile{Services: make(map[string]composeService)}
+	for i, server := range selected {
+		spec := specs[i]
+		// Compose interpolates $ itself, so literals are escaped and secret
+		// markers become the variables
+		value := func(s string) string {
+			s = strings.ReplaceAll(s, "$", "$$")
+			return secretMarker.ReplaceAllStringFunc(s, func(marker string) string {
+				env := marker[1 : len(marker)-1]
+				return "${" + references[server.Name][env] + "}"
+			})
+		}
+
+		service := composeService{
+			Image:       server.Image,
+			User:        spec.User,
+			ExtraHosts:  spec.ExtraHosts,
+			Init:        true,
+			SecurityOpt: []string{"no-new-privileges:true"},
+			Labels:      map[string]string{"docker-mcp-name": server.Name},
+		}
+		for _, arg := range spec.Command {
+			service.Command = append(service.Command, value(arg))
+		}
+		if len(spec.Env) > 0 {
+			service.Environment = make(map[string]string)
+		}
+		for j, e := range spec.Env {
+			if j < spec.Secrets {
+				ref := references[server.Name][e.Name]
+				service.Environment[e.Name] = fmt.Sprintf("${%s:?secret %s is required}", ref, server.Secrets[j].Name)
+				continue
+			}
+			service.Environment[e.Name] = value(e.Value)
+		}
+		for _, volume := range spec.Volumes {
+			source, _, _, err := parseVolume(volume)
+			if err != nil {
+				return nil, fmt.Errorf("server %s: %w", server.Name, err)
+			}
+			if isNamedVolume(source) {
+				if doc.Volumes == nil {
+					doc.Volumes = make(map[string]map[string]any)
+				}
+				doc.Volumes[source] = map[string]any{}
+			}
+			service.Volumes = append(service.Volumes, value(volume))
+		}
+		if server.DisableNetwork {
+			service.NetworkMode = "none"
+		}
+		if port, ok := opts.Ports[server.Name]; ok {
+			service.Expose = []string{strconv.Itoa(port)}
+		} else {
+			service.StdinOpen = true
+		}
+		doc.Services[deployName(server.Name)] = service
+	}
+
+	data, err := marshalYAML(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal compose file: %w", err)
+	}
+	result.YAML = string(data)
+	return result, nil
+}
+
+type k8sObject struct {
+	APIVersion string      `yaml:"apiVersion"`
+	Kind       string      `yaml:"kind"`
+	Metadata   k8sMetadata `yaml:"metadata"`
+	Spec       any         `yaml:"spec"`
+}
+
+type k8sMetadata struct {
+	Name      string            `yaml:"name,omitempty"`
+	Namespace string            `yaml:"namespace,omitempty"`
+	Labels    map[string]string `yaml:"labels,omitempty"`
+}
+
+type k8sSelector struct {
+	MatchLabels map[string]string `yaml:"matchLabels"`
+}
+
+type k8sDeploymentSpec struct {
+	Replicas int         `yaml:"replicas"`
+	Selector k8sSelector `yaml:"selector"`
+	Template struct {
+		Metadata k8sMetadata `yaml:"metadata"`
+		Spec     k8sPodSpec  `yaml:"spec"`
+	} `yaml:"template"`
+}
+
+type k8sPodSpec struct {
+	Containers  []k8sContainer `yaml:"containers"`
+	Volumes     []k8sVolume    `yaml:"volumes,omitempty"`
+	HostAliases []k8sHostAlias `yaml:"hostAliases,omitempty"`
+}
+
+type k8sContainer struct {
+	Name            string             `yaml:"name"`
+	Image           string             `yaml:"image"`
+	Args            []string           `yaml:"args,omitempty"`
+	Env             []k8sEnv           `yaml:"env,omitempty"`
+	Ports           []k8sContainerPort `yaml:"ports,omitempty"`
+	VolumeMounts    []k8sVolumeMount   `yaml:"volumeMounts,omitempty"`
+	Stdin           bool               `yaml:"stdin,omitempty"`
+	SecurityContext k8sSecurityContext `yaml:"securityContext"`
+}
+
+type k8sEnv struct {
+	Name      string        `yaml:"name"`
+	Value     string        `yaml:"value,omitempty"`
+	ValueFrom *k8sEnvSource `yaml:"valueFrom,omitempty"`
+}
+
+type k8sEnvSource struct {
+	SecretKeyRef k8sSecretKeyRef `yaml:"secretKeyRef"`
+}
+
+type k8sSecretKeyRef struct {
+	Name string `yaml:"name"`
+	Key  string `yaml:"key"`
+}
+
+type k8sContainerPort struct {
+	Name          string `yaml:"name"`
+	ContainerPort int    `yaml:"containerPort"`
+}
+
+type k8sVolumeMount struct {
+	Name      string `yaml:"name"`
+	MountPath string `yaml:"mountPath"`
+	ReadOnly  bool   `yaml:"readOnly,omitempty"`
+}
+
+type k8sVolume struct {
+	Name                  string       `yaml:"name"`
+	HostPath              *k8sHostPath `yaml:"hostPath,omitempty"`
+	PersistentVolumeClaim *k8sClaim    `yaml:"persistentVolumeClaim,omitempty"`
+}
+
+type k8sHostPath struct {
+	Path string `yaml:"path"`
+}
+
+type k8sClaim struct {
+	ClaimName string `yaml:"claimName"`
+}
+
+type k8sHostAlias struct {
+	IP        string   `yaml:"ip"`
+	Hostnames []string `yaml:"hostnames"`
+}
+
+type k8sSecurityContext struct {
+	AllowPrivilegeEscalation bool `yaml:"allowPrivilegeEscalation"`
+	RunAsUser                *int `yaml:"runAsUser,omitempty"`
+	RunAsGroup               *int `yaml:"runAsGroup,omitempty"`
+}
+
+type k8sServiceSpec struct {
+	Selector map[string]string `yaml:"selector"`
+	Ports    []k8sServicePort  `yaml:"ports"`
+}
+
+type k8sServicePort struct {
+	Name       string `yaml:"name"`
+	Port       int    `yaml:"port"`
+	TargetPort int    `yaml:"targetPort"`
+}
+
+type k8sExternalSecretSpec struct {
+	SecretStoreRef struct {
+		Name string `yaml:"name"`
+		Kind string `yaml:"kind"`
+	} `yaml:"secretStoreRef"`
+	Target struct {
+		Name string `yaml:"name"`
+	} `yaml:"target"`
+	Data []k8sExternalSecretData `yaml:"data"`
+}
+
+type k8sExternalSecretData struct {
+	SecretKey string `yaml:"secretKey"`
+	RemoteRef struct {
+		Key string `yaml:"key"`
+	} `yaml:"remoteRef"`
+}
+
+type k8sNetworkPolicySpec struct {
+	PodSelector k8sSelector `yaml:"podSelector"`
+	PolicyTypes []string    `yaml:"policyTypes"`
+	Egress      []any       `yaml:"egress"`
+}
+
+// GenerateKubernetes writes a Deployment per image server, a Service for
+// servers with a port, an ExternalSecret (external-secrets.io) that creates
+// the Secret the server's secrets are read from, and a deny-all egress
+// NetworkPolicy for servers without network. Secret values never appear in
+// the manifests; the store is keyed by the catalog secret name.
+func GenerateKubernetes(servers []catalog.Server, opts DeployOptions) (*DeployResult, error) {
+	if opts.SecretStore == "" {
+		opts.SecretStore = "mcp-secrets"
+	}
+	if opts.SecretStoreKind == "" {
+		opts.SecretStoreKind = "ClusterSecretStore"
+	}
+	result := &DeployResult{}
+	selected, specs, err := deployable(servers, opts, func(secret catalog.Secret) string {
+		return secret.Name
+	}, result)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []k8sObject
+	for i, server := range selected {
+		spec := specs[i]
+		name := deployName(server.Name)
+		labels := map[string]string{"app.kubernetes.io/name": name}
+		metadata := k8sMetadata{Name: name, Namespace: opts.Namespace, Labels: labels}
+		secretName := name + "-secrets"
+		// Kubernetes expands $(NAME) from earlier env, so literals are
+		// escaped and secret markers become references to their env
+		value := func(s string) string {
+			s = strings.ReplaceAll(s, "$(", "$$(")
+			return secretMarker.ReplaceAllString(s, "$$($1)")
+		}
+
+		container := k8sContainer{Name: "mcp", Image: server.Image}
+		for _, arg := range spec.Command {
+			container.Args = append(container.Args, value(arg))
+		}
+		for j, e := range spec.Env {
+			env := k8sEnv{Name: e.Name}
+			if j < spec.Secrets {
+				env.ValueFrom = &k8sEnvSource{SecretKeyRef: k8sSecretKeyRef{Name: secretName, Key: e.Name}}
+			} else {
+				env.Value = value(e.Value)
+			}
+			container.Env = append(container.Env, env)
+		}
+
+		var pod k8sPodSpec
+		for j, volume := range spec.Volumes {
+			source, target, readOnly, err := parseVolume(volume)
+			if err != nil {
+				return nil, fmt.Errorf("server %s: %w", server.Name, err)
+			}
+			v := k8sVolume{Name: fmt.Sprintf("volume-%d", j)}
+			if isNamedVolume(source) {
+				v.PersistentVolumeClaim = &k8sClaim{ClaimName: deployName(source)}
+				result.Warnings = append(result.Warnings, fmt.Sprintf("%s mounts volume %s; the PersistentVolumeClaim %s must exist", server.Name, source, deployName(source)))
+			} else if !strings.HasPrefix(source, "/") {
+				return nil, fmt.Errorf("server %s mounts %s; Kubernetes hostPath needs an absolute path", server.Name, source)
+			} else {
+				v.HostPath = &k8sHostPath{Path: source}
+			}
+			pod.Volumes = append(pod.Volumes, v)
+			container.VolumeMounts = append(container.VolumeMounts, k8sVolumeMount{Name: v.Name, MountPath: target, ReadOnly: readOnly})
+		}
+
+		if spec.User != "" {
+			user, group, _ := strings.Cut(spec.User, ":")
+			uid, err := strconv.Atoi(user)
+			if err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("%s runs as user %s; Kubernetes needs a numeric uid, left unset", server.Name, spec.User))
+			} else {
+				container.SecurityContext.RunAsUser = &uid
+				if gid, err := strconv.Atoi(group); err == nil {
+					container.SecurityContext.RunAsGroup = &gid
+				}
+			}
+		}
+
+		for _, host := range spec.ExtraHosts {
+			hostname, ip, _ := strings.Cut(host, ":")
+			if ip == "host-gateway" || ip == "" {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("%s adds host %s; Kubernetes has no host-gateway, left out", server.Name, host))
+				continue
+			}
+			pod.HostAliases = append(pod.HostAliases, k8sHostAlias{IP: ip, Hostnames: []string{hostname}})
+		}
+
+		port, hasPort := opts.Ports[server.Name]
+		if hasPort {
+			container.Ports = []k8sContainerPort{{Name: "mcp", ContainerPort: port}}
+		} else {
+			container.Stdin = true
+		}
+		pod.Containers = []k8sContainer{container}
+
+		deployment := k8sDeploymentSpec{Replicas: 1, Selector: k8sSelector{MatchLabels: labels}}
+		deployment.Template.Metadata = k8sMetadata{Labels: labels}
+		deployment.Template.Spec = pod
+		objects = append(objects, k8sObject{APIVersion: "apps/v1", Kind: "Deployment", Metadata: metadata, Spec: deployment})
+
+		if hasPort {
+			service := k8sServiceSpec{Selector: labels, Ports: []k8sServicePort{{Name: "mcp", Port: port, TargetPort: port}}}
+			objects = append(objects, k8sObject{APIVersion: "v1", Kind: "Service", Metadata: metadata, Spec: service})
+		}
+
+		if spec.Secrets > 0 {
+			external := k8sExternalSecretSpec{}
+			external.SecretStoreRef.Name = opts.SecretStore
+			external.SecretStoreRef.Kind = opts.SecretStoreKind
+			external.Target.Name = secretName
+			for j, secret := range server.Secrets {
+				data := k8sExternalSecretData{SecretKey: spec.Env[j].Name}
+				data.RemoteRef.Key = secret.Name
+				external.Data = append(external.Data, data)
+			}
+			objects = append(objects, k8sObject{
+				APIVersion: "external-secrets.io/v1",
+				Kind:       "ExternalSecret",
+				Metadata:   k8sMetadata{Name: secretName, Namespace: opts.Namespace, Labels: labels},
+				Spec:       external,
+			})
+		}
+
+		if server.DisableNetwork {
+			objects = append(objects, k8sObject{
+				APIVersion: "networking.k8s.io/v1",
+				Kind:       "NetworkPolicy",
+				Metadata:   k8sMetadata{Name: name + "-deny-egress", Namespace: opts.Namespace, Labels: labels},
+				Spec:       k8sNetworkPolicySpec{PodSelector: k8sSelector{MatchLabels: labels}, PolicyTypes: []string{"Egress"}, Egress: []any{}},
+			})
+		}
+	}
+
+	var documents []string
+	for _, object := range objects {
+		data, err := marshalYAML(object)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s %s: %w", object.Kind, object.Metadata.Name, err)
+		}
+		documents = append(documents, string(data))
+	}
+	result.YAML = strings.Join(documents, "---\n")
+	return result, nil
+}
@@ -0,0 +1,261 @@
+package catalogs
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"gopkg.in/yaml.v3"
+)
+
+func TestGenerateCompose(t *testing.T) {
+	servers := []catalog.Server{
+		{
+			Name:           "com-example-notes",
+			Type:           "server",
+			Image:          "docker.io/example/notes:1.0.0",
+			Secrets:        []catalog.Secret{{Name: "com-example-notes.api_key", Env: "API_KEY"}},
+			Env:            []catalog.Env{{Name: "REGION", Value: "{{com-example-notes.region}}"}},
+			Volumes:        []string{"{{com-example-notes.data}}:/data"},
+			User:           "1000:1000",
+			DisableNetwork: true,
+			Command:        []string{"--verbose"},
+		},
+		{
+			Name:    "com-example-remote",
+			Type:    "remote",
+			Remote:  catalog.Remote{URL: "https://mcp.example.com/mcp", Transport: "sse"},
+			Secrets: []catalog.Secret{{Name: "com-example-remote.token", Env: "TOKEN"}},
+		},
+		{
+			Name:       "io.example/http",
+			Type:       "server",
+			Image:      "docker.io/example/http:2.0.0",
+			LongLived:  true,
+			Secrets:    []catalog.Secret{{Name: "io.example/http.token", Env: "TOKEN"}},
+			Command:    []string{"--token=${TOKEN}"},
+			Volumes:    []string{"cache:/cache:ro"},
+			User:       "app",
+			ExtraHosts: []string{"db:10.0.0.5", "host.docker.internal:host-gateway"},
+			AllowHosts: []string{"api.example.com:443"},
+		},
+	}
+	result, err := GenerateCompose(servers, DeployOptions{
+		Config: map[string]any{"com-example-notes": map[string]any{"region": "eu$1", "data": "/srv/notes"}},
+		Ports:  map[string]int{"io.example/http": 8080},
+	})
+	if err != nil {
+		t.Fatalf("GenerateCompose failed: %v", err)
+	}
+	var doc composeFile
+	if err := yaml.Unmarshal([]byte(result.YAML), &doc); err != nil {
+		t.Fatalf("Compose file is not YAML: %v\n%s", err, result.YAML)
+	}
+
+	notes := doc.Services["com-example-notes"]
+	if notes.Image != "docker.io/example/notes:1.0.0" || notes.User != "1000:1000" || notes.NetworkMode != "none" || !notes.StdinOpen {
+		t.Errorf("Unexpected service %+v", notes)
+	}
+	expectedEnv := map[string]string{
+		"API_KEY": "${COM_EXAMPLE_NOTES_API_KEY:?secret com-example-notes.api_key is required}",
+		"REGION":  "eu$$1",
+	}
+	if !reflect.DeepEqual(notes.Environment, expectedEnv) {
+		t.Errorf("Unexpected environment %v", notes.Environment)
+	}
+	if !reflect.DeepEqual(notes.Volumes, []string{"/srv/notes:/data"}) || !reflect.DeepEqual(notes.Command, []string{"--verbose"}) {
+		t.Errorf("Unexpected volumes %v or command %v", notes.Volumes, notes.Command)
+	}
+
+	http := doc.Services["io-example-http"]
+	if !reflect.DeepEqual(http.Command, []string{"--token=${IO_EXAMPLE_HTTP_TOKEN}"}) || !reflect.DeepEqual(http.Expose, []string{"8080"}) || http.StdinOpen {
+		t.Errorf("Unexpected service %+v", http)
+	}
+	if _, ok := doc.Volumes["cache"]; !ok {
+		t.Errorf("Expected the named volume declared, got %v", doc.Volumes)
+	}
+	if _, ok := doc.Services["com-example-remote"]; ok {
+		t.Error("Remotes should not become services")
+	}
+
+	if len(result.Secrets) != 2 || result.Secrets[0].Reference != "COM_EXAMPLE_NOTES_API_KEY" {
+		t.Errorf("Unexpected secrets %+v", result.Secrets)
+	}
+	if len(result.Warnings) != 2 || !strings.Contains(result.Warnings[1], "api.example.com:443") {
+		t.Errorf("Unexpected warnings %v", result.Warnings)
+	}
+}
+
+func decodeManifests(t *testing.T, result *DeployResult) map[string]map[string]any {
+	t.Helper()
+	objects := make(map[string]map[string]any)
+	decoder := yaml.NewDecoder(strings.NewReader(result.YAML))
+	for {
+		var object map[string]any
+		if err := decoder.Decode(&object); err != nil {
+			break
+		}
+		metadata := object["metadata"].(map[string]any)
+		objects[object["kind"].(string)+"/"+metadata["name"].(string)] = object
+	}
+	return objects
+}
+
+func TestGenerateKubernetes(t *testing.T) {
+	servers := []catalog.Server{
+		{
+			Name:           "com-example-notes",
+			Type:           "server",
+			Image:          "docker.io/example/notes:1.0.0",
+			Secrets:        []catalog.Secret{{Name: "com-example-notes.api_key", Env: "API_KEY"}},
+			Env:            []catalog.Env{{Name: "REGION", Value: "{{com-example-notes.region}}"}},
+			Volumes:        []string{"{{com-example-notes.data}}:/data"},
+			User:           "1000:1000",
+			DisableNetwork: true,
+			Command:        []string{"--verbose"},
+		},
+		{
+			Name:    "com-example-remote",
+			Type:    "remote",
+			Remote:  catalog.Remote{URL: "https://mcp.example.com/mcp", Transport: "sse"},
+			Secrets: []catalog.Secret{{Name: "com-example-remote.token", Env: "TOKEN"}},
+		},
+		{
+			Name:       "io.example/http",
+			Type:       "server",
+			Image:      "docker.io/example/http:2.0.0",
+			LongLived:  true,
+			Secrets:    []catalog.Secret{{Name: "io.example/http.token", Env: "TOKEN"}},
+			Command:    []string{"--token=${TOKEN}"},
+			Volumes:    []string{"cache:/cache:ro"},
+			User:       "app",
+			ExtraHosts: []string{"db:10.0.0.5", "host.docker.internal:host-gateway"},
+			AllowHosts: []string{"api.example.com:443"},
+		},
+	}
+	result, err := GenerateKubernetes(servers, DeployOptions{
+		Namespace: "mcp",
+		Config:    map[string]any{"com-example-notes": map[string]any{"region": "eu$1", "data": "/srv/notes"}},
+		Ports:     map[string]int{"io.example/http": 8080},
+	})
+	if err != nil {
+		t.Fatalf("GenerateKubernetes failed: %v", err)
+	}
+	objects := decodeManifests(t, result)
+
+	var kinds []string
+	for kind := range objects {
+		kinds = append(kinds, kind)
+	}
+	expected := []string{
+		"Deployment/com-example-notes", "ExternalSecret/com-example-notes-secrets", "NetworkPolicy/com-example-notes-deny-egress",
+		"Deployment/io-example-http", "Service/io-example-http", "ExternalSecret/io-example-http-secrets",
+	}
+	if len(objects) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, kinds)
+	}
+	for _, kind := range expected {
+		if _, ok := objects[kind]; !ok {
+			t.Errorf("Missing %s in %v", kind, kinds)
+		}
+	}
+
+	var deployment struct {
+		Spec k8sDeploymentSpec `yaml:"spec"`
+	}
+	data, _ := yaml.Marshal(objects["Deployment/com-example-notes"])
+	if err := yaml.Unmarshal(data, &deployment); err != nil {
+		t.Fatalf("Invalid deployment: %v", err)
+	}
+	pod := deployment.Spec.Template.Spec
+	container := pod.Containers[0]
+	if container.Env[0].ValueFrom == nil || container.Env[0].ValueFrom.SecretKeyRef != (k8sSecretKeyRef{Name: "com-example-notes-secrets", Key: "API_KEY"}) {
+		t.Errorf("Expected the secret from the Secret, got %+v", container.Env[0])
+	}
+	if container.Env[1] != (k8sEnv{Name: "REGION", Value: "eu$1"}) || !container.Stdin {
+		t.Errorf("Unexpected container %+v", container)
+	}
+	if *container.SecurityContext.RunAsUser != 1000 || *container.SecurityContext.RunAsGroup != 1000 {
+		t.Errorf("Unexpected security context %+v", container.SecurityContext)
+	}
+	if pod.Volumes[0].HostPath.Path != "/srv/notes" || container.VolumeMounts[0].MountPath != "/data" {
+		t.Errorf("Unexpected volumes %+v %+v", pod.Volumes, container.VolumeMounts)
+	}
+
+	data, _ = yaml.Marshal(objects["Deployment/io-example-http"])
+	if err := yaml.Unmarshal(data, &deployment); err != nil {
+		t.Fatalf("Invalid deployment: %v", err)
+	}
+	pod = deployment.Spec.Template.Spec
+	container = pod.Containers[0]
+	if !reflect.DeepEqual(container.Args, []string{"--token=$(TOKEN)"}) || container.Ports[0].ContainerPort != 8080 {
+		t.Errorf("Unexpected container %+v", container)
+	}
+	if pod.Volumes[0].PersistentVolumeClaim.ClaimName != "cache" || !container.VolumeMounts[0].ReadOnly {
+		t.Errorf("Unexpected volumes %+v %+v", pod.Volumes, container.VolumeMounts)
+	}
+	if len(pod.HostAliases) != 1 || pod.HostAliases[0].IP != "10.0.0.5" {
+		t.Errorf("Unexpected host aliases %+v", pod.HostAliases)
+	}
+
+	secret := objects["ExternalSecret/io-example-http-secrets"]
+	if secret["apiVersion"] != "external-secrets.io/v1" || secret["metadata"].(map[string]any)["namespace"] != "mcp" || !strings.Contains(result.YAML, "key: io.example/http.token") {
+		t.Errorf("Unexpected external secret %v", secret)
+	}
+	if !strings.Contains(result.YAML, "egress: []") {
+		t.Errorf("Expected a deny-all egress policy:\n%s", result.YAML)
+	}
+	for _, warning := range []string{"PersistentVolumeClaim cache", "numeric uid", "host-gateway"} {
+		if !strings.Contains(strings.Join(result.Warnings, "\n"), warning) {
+			t.Errorf("Expected a warning about %s, got %v", warning, result.Warnings)
+		}
+	}
+}
+
+func TestGenerateKubernetesRelativeHostPath(t *testing.T) {
+	for _, source := range []string{"./data", "~/data"} {
+		servers := []catalog.Server{{Name: "com-example-notes", Type: "server", Image: "docker.io/example/notes:1.0.0", Volumes: []string{source + ":/data"}}}
+		if _, err := GenerateKubernetes(servers, DeployOptions{}); err == nil || !strings.Contains(err.Error(), "absolute path") {
+			t.Errorf("%s: expected an absolute path error, got %v", source, err)
+		}
+		// compose resolves relative paths itself
+		if _, err := GenerateCompose(servers, DeployOptions{}); err != nil {
+			t.Errorf("%s: GenerateCompose failed: %v", source, err)
+		}
+	}
+}
+
+func TestGenerateUnresolvedConfig(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		server catalog.Server
+	}{
+		{name: "env", server: catalog.Server{Name: "com-example-notes", Type: "server", Image: "docker.io/example/notes:1.0.0", Env: []catalog.Env{{Name: "REGION", Value: "{{com-example-notes.region}}"}}}},
+		{name: "volume", server: catalog.Server{Name: "com-example-notes", Type: "server", Image: "docker.io/example/notes:1.0.0", Volumes: []string{"{{com-example-notes.data}}:/data"}}},
+		{name: "command", server: catalog.Server{Name: "com-example-notes", Type: "server", Image: "docker.io/example/notes:1.0.0", Command: []string{"--region={{com-example-notes.region}}"}}},
+	} {
+		_, err := GenerateKubernetes([]catalog.Server{tc.server}, DeployOptions{})
+		var unresolved *UnresolvedPlaceholderError
+		if !errors.As(err, &unresolved) || unresolved.Server != "com-example-notes" {
+			t.Errorf("%s: expected UnresolvedPlaceholderError, got %v", tc.name, err)
+		}
+	}
+}
+
+func TestGenerateNameCollision(t *testing.T) {
+	servers := []catalog.Server{{Name: "com.example/notes", Image: "a"}, {Name: "com-example-notes", Image: "b"}}
+	if _, err := GenerateCompose(servers, DeployOptions{}); err == nil {
+		t.Error("Expected error for servers deploying under the same name")
+	}
+}
+
+func TestParseDeployTarget(t *testing.T) {
+	if _, err := ParseDeployTarget("nomad"); err == nil {
+		t.Error("Expected error for unknown target")
+	}
+	if target, err := ParseDeployTarget("kubernetes"); err != nil || target != DeployKubernetes {
+		t.Errorf("Unexpected %v, %v", target, err)
+	}
+}
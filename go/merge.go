@@ -0,0 +1,344 @@
+package catalogs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MergeConflict is a field changed both locally and upstream. The merged
+// document keeps the local value.
+type MergeConflict struct {
+	Path     string `json:"path" yaml:"path"`
+	Base     string `json:"base,omitempty" yaml:"base,omitempty"`
+	Local    string `json:"local,omitempty" yaml:"local,omitempty"`
+	Upstream string `json:"upstream,omitempty" yaml:"upstream,omitempty"`
+}
+
+func (c MergeConflict) String() string {
+	show := func(s string) string {
+		if s == "" {
+			return "(absent)"
+		}
+		return s
+	}
+	return fmt.Sprintf("%s:\n  base:     %s\n  local:    %s\n  upstream: %s", c.Path, show(c.Base), show(c.Local), show(c.Upstream))
+}
+
+// MergeResult is the outcome of MergeDocuments
+type MergeResult struct {
+	Document  []byte
+	Conflicts []MergeConflict
+}
+
+// MergeDocuments three-way merges catalog documents (server.yaml files or
+// catalog servers, in JSON or YAML): base is the previously generated output,
+// local the hand-edited file and upstream a fresh transform. Upstream changes
+// are applied, local edits are kept, and fields changed on both sides are
+// reported as conflicts with the local value kept. Lists of objects with a
+// name (secrets, env, tools) are merged by name. The document keeps the
+// local key order and format.
+func MergeDocuments(base, local, upstream []byte) (*MergeResult, error) {
+	var nodes [3]*yaml.Node
+	for i, data := range [][]byte{base, local, upstream} {
+		var doc yaml.Node
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse %s document: %w", []string{"base", "local", "upstream"}[i], err)
+		}
+		if len(doc.Content) > 0 {
+			nodes[i] = doc.Content[0]
+		}
+	}
+
+	var conflicts []MergeConflict
+	merged := mergeNodes(nodes[0], nodes[1], nodes[2], "", &conflicts)
+
+	var document []byte
+	var err error
+	if json.Valid(local) {
+		var raw []byte
+		if raw, err = nodeJSON(merged); err == nil {
+			var indented bytes.Buffer
+			err = json.Indent(&indented, raw, "", "  ")
+			document = indented.Bytes()
+		}
+	} else {
+		document, err = marshalYAML(merged)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal merged document: %w", err)
+	}
+	return &MergeResult{Document: document, Conflicts: conflicts}, nil
+}
+
+// nodeJSON renders a node as JSON in the node's key order
+func nodeJSON(node *yaml.Node) ([]byte, error) {
+	if node == nil {
+		return []byte("null"), nil
+	}
+	var buf bytes.Buffer
+	switch node.Kind {
+	case yaml.AliasNode:
+		return nodeJSON(node.Alias)
+	case yaml.MappingNode:
+		buf.WriteByte('{')
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, err := json.Marshal(node.Content[i].Value)
+			if err != nil {
+				return nil, err
+			}
+			value, err := nodeJSON(node.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(key)
+			buf.WriteByte(':')
+			buf.Write(value)
+		}
+		buf.WriteByte('}')
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, item := range node.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			value, err := nodeJSON(item)
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(value)
+		}
+		buf.WriteByte(']')
+	default:
+		var v any
+		if err := node.Decode(&v); err != nil {
+			return nil, err
+		}
+		return json.Marshal(v)
+	}
+	return buf.Bytes(), nil
+}
+
+// nodeValue decodes a node for comparison; absent nodes are nil
+func nodeValue(node *yaml.Node) any {
+	if node == nil {
+		return nil
+	}
+	var v any
+	if err := node.Decode(&v); err != nil {
+		return nil
+	}
+	return v
+}
+
+func nodesEqual(a, b *yaml.Node) bool {
+	return reflect.DeepEqual(nodeValue(a), nodeValue(b))
+}
+
+// nodeString renders a node on one line for conflict reports
+func nodeString(node *yaml.Node) string {
+	v := nodeValue(node)
+	if v == nil {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func mergeNodes(base, local, upstream *yaml.Node, path string, conflicts *[]MergeConflict) *yaml.Node {
+	switch {
+	case nodesEqual(local, upstream):
+		return local
+	case nodesEqual(base, local):
+		return upstream
+	case nodesEqual(base, upstream):
+		return local
+	}
+
+	if isKind(local, yaml.MappingNode) && isKind(upstream, yaml.MappingNode) && (base == nil || isKind(base, yaml.MappingNode)) {
+		return mergeEntries(mappingEntries(base), mappingEntries(local), mappingEntries(upstream), local, path, conflicts)
+	}
+	if namedSequence(local) && namedSequence(upstream) && (base == nil || namedSequence(base)) {
+		return mergeEntries(namedEntries(base), namedEntries(local), namedEntries(upstream), local, path, conflicts)
+	}
+
+	*conflicts = append(*conflicts, MergeConflict{
+		Path:     path,
+		Base:     nodeString(base),
+		Local:    nodeString(local),
+		Upstream: nodeString(upstream),
+	})
+	return local
+}
+
+func isKind(node *yaml.Node, kind yaml.Kind) bool {
+	return node != nil && node.Kind == kind
+}
+
+// keyedEntries are the children of a mapping, or the items of a sequence of
+// named objects, in document order
+type keyedEntries struct {
+	order  []string
+	keys   map[string]*yaml.Node
+	values map[string]*yaml.Node
+}
+
+func (e *keyedEntries) add(key string, keyNode, value *yaml.Node) {
+	if e.values == nil {
+		e.keys = make(map[string]*yaml.Node)
+		e.values = make(map[string]*yaml.Node)
+	}
+	if _, ok := e.values[key]; !ok {
+		e.order = append(e.order, key)
+	}
+	e.keys[key] = keyNode
+	e.values[key] = value
+}
+
+func mappingEntries(node *yaml.Node) keyedEntries {
+	var entries keyedEntries
+	if node != nil {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			entries.add(node.Content[i].Value, node.Content[i], node.Content[i+1])
+		}
+	}
+	return entries
+}
+
+// itemName returns the scalar name of a mapping item
+func itemName(item *yaml.Node) (string, bool) {
+	if !isKind(item, yaml.MappingNode) {
+		return "", false
+	}
+	for i := 0; i+1 < len(item.Content); i += 2 {
+		if item.Content[i].Value == "name" && item.Content[i+1].Kind == yaml.ScalarNode {
+			return item.Content[i+1].Value, true
+		}
+	}
+	return "", false
+}
+
+// namedSequence reports whether every item of a sequence has a name
+func namedSequence(node *yaml.Node) bool {
+	if !isKind(node, yaml.SequenceNode) {
+		return false
+	}
+	for _, item := range node.Content {
+		if _, ok := itemName(item); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func namedEntries(node *yaml.Node) keyedEntries {
+	var entries keyedEntries
+	if node != nil {
+		for _, item := range node.Content {
+			name, _ := itemName(item)
+			entries.add(name, nil, item)
+		}
+	}
+	return entries
+}
+
+// mergeEntries merges children by key, keeping the local order and
+// appending children that are new upstream
+func mergeEntries(base, local, upstream keyedEntries, localNode *yaml.Node, path string, conflicts *[]MergeConflict) *yaml.Node {
+	merged := &yaml.Node{Kind: localNode.Kind, Tag: localNode.Tag, Style: localNode.Style}
+
+	order := append([]string{}, local.order...)
+	for _, key := range upstream.order {
+		if _, ok := local.values[key]; !ok {
+			order = append(order, key)
+		}
+	}
+
+	for _, key := range order {
+		childPath := joinPath(path, key)
+		if localNode.Kind == yaml.SequenceNode {
+			childPath = fmt.Sprintf("%s[%s]", path, key)
+		}
+		value := mergeNodes(base.values[key], local.values[key], upstream.values[key], childPath, conflicts)
+		if value == nil {
+			continue
+		}
+		if localNode.Kind == yaml.MappingNode {
+			keyNode := local.keys[key]
+			if keyNode == nil {
+				keyNode = upstream.keys[key]
+			}
+			merged.Content = append(merged.Content, keyNode)
+		}
+		merged.Content = append(merged.Content, value)
+	}
+	return merged
+}
+
+// FormatMergeConflicts renders conflicts for review
+func FormatMergeConflicts(conflicts []MergeConflict) string {
+	lines := make([]string, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		lines = append(lines, conflict.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// MergeCatalogEntry is MergeDocuments where base and upstream may also be
+// registry JSON. Registry JSON is transformed and rendered in the shape of
+// local: a server.yaml entry or a catalog server.
+func MergeCatalogEntry(base, local, upstream []byte) (*MergeResult, error) {
+	var localProbe map[string]any
+	if err := yaml.Unmarshal(local, &localProbe); err != nil {
+		return nil, fmt.Errorf("failed to parse local document: %w", err)
+	}
+	_, serverYAML := localProbe["about"]
+
+	render := func(data []byte) ([]byte, error) {
+		var probe map[string]any
+		if err := yaml.Unmarshal(data, &probe); err != nil {
+			return nil, err
+		}
+		if _, ok := probe["server"]; !ok {
+			return data, nil
+		}
+		server, err := ParseServerVersion(data)
+		if err != nil {
+			return nil, err
+		}
+		if serverYAML {
+			return MarshalCatalogEntry(server)
+		}
+		return json.Marshal(server)
+	}
+
+	base, err := render(base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render base: %w", err)
+	}
+	upstream, err = render(upstream)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render upstream: %w", err)
+	}
+	return MergeDocuments(base, local, upstream)
+}
@@ -0,0 +1,156 @@
+package catalogs
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const mergeBase = `name: com-example-weather
+type: server
+image: example/weather@sha256:1111
+about:
+  title: Weather
+  description: Weather forecasts
+config:
+  secrets:
+  - name: com-example-weather.api_key
+    env: API_KEY
+  env:
+  - name: UNITS
+    value: '{{com-example-weather.units}}'
+`
+
+const mergeLocal = `name: com-example-weather
+type: server
+image: example/weather@sha256:1111
+about:
+  title: Weather Forecasts
+  description: Weather forecasts
+dynamic:
+  tools: true
+config:
+  secrets:
+  - name: com-example-weather.api_key
+    env: API_KEY
+    example: abc123...
+  env:
+  - name: UNITS
+    value: '{{com-example-weather.units}}'
+`
+
+func TestMergeDocuments(t *testing.T) {
+	upstream := strings.NewReplacer(
+		"sha256:1111", "sha256:2222",
+		"    env: API_KEY\n", "    env: API_KEY\n  - name: com-example-weather.token\n    env: TOKEN\n",
+	).Replace(mergeBase)
+
+	result, err := MergeDocuments([]byte(mergeBase), []byte(mergeLocal), []byte(upstream))
+	if err != nil {
+		t.Fatalf("MergeDocuments failed: %v", err)
+	}
+	if len(result.Conflicts) > 0 {
+		t.Errorf("Expected no conflicts, got %v", result.Conflicts)
+	}
+
+	var merged catalogEntry
+	if err := yaml.Unmarshal(result.Document, &merged); err != nil {
+		t.Fatalf("Failed to parse merged document: %v", err)
+	}
+	// Upstream changes
+	if merged.Image != "example/weather@sha256:2222" {
+		t.Errorf("Expected upstream digest, got %s", merged.Image)
+	}
+	if len(merged.Config.Secrets) != 2 || merged.Config.Secrets[1].Env != "TOKEN" {
+		t.Errorf("Expected the new upstream secret, got %+v", merged.Config.Secrets)
+	}
+	// Local edits
+	if merged.About.Title != "Weather Forecasts" {
+		t.Errorf("Expected the local title, got %s", merged.About.Title)
+	}
+	if merged.Config.Secrets[0].Example != "abc123..." || !merged.Dynamic.Tools {
+		t.Errorf("Expected local additions to be kept, got %+v and %+v", merged.Config.Secrets, merged.Dynamic)
+	}
+	document := string(result.Document)
+	// Local key order
+	if strings.Index(document, "about:") > strings.Index(document, "dynamic:") {
+		t.Errorf("Expected local key order to be kept:\n%s", document)
+	}
+}
+
+func TestMergeDocumentsJSONKeyOrder(t *testing.T) {
+	base := `{"name": "com-example-weather", "type": "server", "image": "example/weather@sha256:1111"}`
+	local := `{"type": "server", "name": "com-example-weather", "title": "Weather", "image": "example/weather@sha256:1111"}`
+	upstream := strings.Replace(base, "sha256:1111", "sha256:2222", 1)
+
+	result, err := MergeDocuments([]byte(base), []byte(local), []byte(upstream))
+	if err != nil {
+		t.Fatalf("MergeDocuments failed: %v", err)
+	}
+	expected := `{
+  "type": "server",
+  "name": "com-example-weather",
+  "title": "Weather",
+  "image": "example/weather@sha256:2222"
+}`
+	if string(result.Document) != expected {
+		t.Errorf("Expected the local key order:\n%s", result.Document)
+	}
+}
+
+func TestMergeDocumentsConflict(t *testing.T) {
+	upstream := strings.Replace(mergeBase, "title: Weather\n", "title: Weather MCP\n", 1)
+
+	result, err := MergeDocuments([]byte(mergeBase), []byte(mergeLocal), []byte(upstream))
+	if err != nil {
+		t.Fatalf("MergeDocuments failed: %v", err)
+	}
+	if len(result.Conflicts) != 1 {
+		t.Fatalf("Expected one conflict, got %v", result.Conflicts)
+	}
+	conflict := result.Conflicts[0]
+	if conflict.Path != "about.title" || conflict.Base != "Weather" || conflict.Local != "Weather Forecasts" || conflict.Upstream != "Weather MCP" {
+		t.Errorf("Unexpected conflict %+v", conflict)
+	}
+	if !strings.Contains(string(result.Document), "title: Weather Forecasts") {
+		t.Errorf("Expected the local value to be kept on conflict:\n%s", result.Document)
+	}
+	if !strings.Contains(FormatMergeConflicts(result.Conflicts), "upstream: Weather MCP") {
+		t.Errorf("Unexpected conflict report %s", FormatMergeConflicts(result.Conflicts))
+	}
+}
+
+func TestMergeDocumentsUpstreamRemoval(t *testing.T) {
+	upstream := strings.Replace(mergeBase, "  env:\n  - name: UNITS\n    value: '{{com-example-weather.units}}'\n", "", 1)
+
+	result, err := MergeDocuments([]byte(mergeBase), []byte(mergeLocal), []byte(upstream))
+	if err != nil {
+		t.Fatalf("MergeDocuments failed: %v", err)
+	}
+	if strings.Contains(string(result.Document), "UNITS") {
+		t.Errorf("Expected env removed upstream to be removed:\n%s", result.Document)
+	}
+}
+
+func TestMergeCatalogEntryFromRegistry(t *testing.T) {
+	registry := remoteRegistryJSON("https://grafana.example.com/mcp")
+	server, err := ParseServerVersion([]byte(registry))
+	if err != nil {
+		t.Fatalf("ParseServerVersion failed: %v", err)
+	}
+	local, err := MarshalCatalogEntry(server)
+	if err != nil {
+		t.Fatalf("MarshalCatalogEntry failed: %v", err)
+	}
+	local = append(local, []byte("dynamic:\n  tools: true\n")...)
+
+	result, err := MergeCatalogEntry([]byte(registry), local, []byte(remoteRegistryJSON("https://grafana.example.com/v2/mcp")))
+	if err != nil {
+		t.Fatalf("MergeCatalogEntry failed: %v", err)
+	}
+	document := string(result.Document)
+	if !strings.Contains(document, "url: https://grafana.example.com/v2/mcp") || !strings.Contains(document, "dynamic:") {
+		t.Errorf("Expected upstream URL and local dynamic block:\n%s", document)
+	}
+}
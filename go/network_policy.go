@@ -0,0 +1,206 @@
+package catalogs
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+	"gopkg.in/yaml.v3"
+)
+
+// NetworkPolicy is the egress policy applied to local (image) catalog servers
+type NetworkPolicy struct {
+	// Publishers maps a publisher namespace, the part of the registry name
+	// before the slash (e.g. io.github.user), to extra hosts its servers may reach
+	Publishers map[string][]string `yaml:"publishers,omitempty" json:"publishers,omitempty"`
+	// Offline lists registry server names that get no network at all
+	Offline []string `yaml:"offline,omitempty" json:"offline,omitempty"`
+	// SkipRepositoryHost stops the repository host from being allowed
+	SkipRepositoryHost bool `yaml:"skipRepositoryHost,omitempty" json:"skipRepositoryHost,omitempty"`
+}
+
+// LoadNetworkPolicy reads a network policy YAML file
+func LoadNetworkPolicy(path string) (*NetworkPolicy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var policy NetworkPolicy
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse network policy %s: %w", path, err)
+	}
+	return &policy, nil
+}
+
+// HostJustification explains why a host is in AllowHosts
+type HostJustification struct {
+	Host    string   `json:"host"`
+	Reasons []string `json:"reasons"`
+}
+
+func (j HostJustification) String() string {
+	return fmt.Sprintf("%s (%s)", j.Host, strings.Join(j.Reasons, "; "))
+}
+
+// EgressDecision is the network policy derived for one server
+type EgressDecision struct {
+	DisableNetwork bool                `json:"disableNetwork,omitempty"`
+	AllowHosts     []HostJustification `json:"allowHosts,omitempty"`
+	// Reason explains DisableNetwork
+	Reason string `json:"reason,omitempty"`
+}
+
+// Apply sets DisableNetwork and AllowHosts on a catalog server
+func (d EgressDecision) Apply(server *catalog.Server) {
+	server.DisableNetwork = d.DisableNetwork
+	server.AllowHosts = nil
+	for _, host := range d.AllowHosts {
+		server.AllowHosts = append(server.AllowHosts, host.Host)
+	}
+}
+
+// Justifications describes the decision, one line per allowed host
+func (d EgressDecision) Justifications() []string {
+	if d.DisableNetwork {
+		return []string{fmt.Sprintf("network disabled: %s", d.Reason)}
+	}
+	var lines []string
+	for _, host := range d.AllowHosts {
+		lines = append(lines, fmt.Sprintf("allowing %s", host))
+	}
+	return lines
+}
+
+func publisherNamespace(fullName string) string {
+	namespace, _, _ := strings.Cut(fullName, "/")
+	return namespace
+}
+
+// hostSpec turns a URL into the host:port form AllowHosts expects. URLs with
+// interpolated hosts can't be pinned and are skipped.
+func hostSpec(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" || strings.ContainsAny(u.Host, "{}$") {
+		return ""
+	}
+	port := u.Port()
+	if port == "" {
+		switch u.Scheme {
+		case "https", "wss":
+			port = "443"
+		case "http", "ws":
+			port = "80"
+		default:
+			return ""
+		}
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// allowlistHostSpec normalizes a publisher allowlist entry, which may be a
+// bare host, a host:port or a URL
+func allowlistHostSpec(entry string) string {
+	if strings.Contains(entry, "://") {
+		return hostSpec(entry)
+	}
+	if _, _, err := net.SplitHostPort(strings.SplitN(entry, "/", 2)[0]); err == nil {
+		return entry
+	}
+	return net.JoinHostPort(entry, "443")
+}
+
+var declaredURLPattern = regexp.MustCompile(`https?://[^\s"'<>]+`)
+
+// declaredURLs returns the remote URLs an image server declares in the
+// values and defaults of its package's env vars and arguments. The server's
+// own remotes are where the publisher hosts it, not what the image reaches.
+func declaredURLs(serverDetail ServerDetail) map[string]string {
+	urls := make(map[string]string)
+	add := func(where, value string) {
+		for _, u := range declaredURLPattern.FindAllString(value, -1) {
+			if _, ok := urls[u]; !ok {
+				urls[u] = fmt.Sprintf("URL %s declared in %s", u, where)
+			}
+		}
+	}
+	for _, pkg := range serverDetail.Packages {
+		for _, ev := range pkg.EnvironmentVariables {
+			add("env "+ev.Name, ev.Value)
+			add("env "+ev.Name, ev.Default)
+			for _, v := range ev.Variables {
+				add("env "+ev.Name, v.Default)
+			}
+		}
+		for _, arg := range append(append([]model.Argument{}, pkg.PackageArguments...), pkg.RuntimeArguments...) {
+			where := "argument " + arg.Name
+			if arg.Name == "" {
+				where = "positional argument"
+			}
+			add(where, arg.Value)
+			add(where, arg.Default)
+		}
+	}
+	return urls
+}
+
+// DeriveEgress derives the network policy for a registry server. Remote
+// servers don't run locally and get an empty decision. An empty allowHosts
+// leaves an image unrestricted, so an image server without any host to allow
+// gets no network.
+func (p *NetworkPolicy) DeriveEgress(serverDetail ServerDetail, server *catalog.Server) EgressDecision {
+	if server.Type != "server" {
+		return EgressDecision{}
+	}
+	if server.DisableNetwork {
+		return EgressDecision{DisableNetwork: true, Reason: "runtime arguments request --network none"}
+	}
+	for _, offline := range p.Offline {
+		if offline == serverDetail.Name {
+			return EgressDecision{DisableNetwork: true, Reason: "server is marked offline in the network policy"}
+		}
+	}
+
+	reasons := make(map[string][]string)
+	allow := func(host, reason string) {
+		if host == "" {
+			return
+		}
+		for _, existing := range reasons[host] {
+			if existing == reason {
+				return
+			}
+		}
+		reasons[host] = append(reasons[host], reason)
+	}
+
+	for u, reason := range declaredURLs(serverDetail) {
+		allow(hostSpec(u), reason)
+	}
+	if serverDetail.Repository != nil && serverDetail.Repository.URL != "" && !p.SkipRepositoryHost {
+		allow(hostSpec(serverDetail.Repository.URL), fmt.Sprintf("repository %s", serverDetail.Repository.URL))
+	}
+	namespace := publisherNamespace(serverDetail.Name)
+	for _, entry := range p.Publishers[namespace] {
+		allow(allowlistHostSpec(entry), fmt.Sprintf("allowlisted for publisher %s", namespace))
+	}
+
+	if len(reasons) == 0 {
+		return EgressDecision{DisableNetwork: true, Reason: "no declared URL, repository or publisher allowlist entry to allow"}
+	}
+
+	var decision EgressDecision
+	for host, hostReasons := range reasons {
+		sort.Strings(hostReasons)
+		decision.AllowHosts = append(decision.AllowHosts, HostJustification{Host: host, Reasons: hostReasons})
+	}
+	sort.Slice(decision.AllowHosts, func(i, j int) bool {
+		return decision.AllowHosts[i].Host < decision.AllowHosts[j].Host
+	})
+	return decision
+}
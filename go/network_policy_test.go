@@ -0,0 +1,121 @@
+package catalogs
+
+import (
+	"strings"
+	"testing"
+)
+
+const egressRegistryJSON = `{
+	"server": {
+		"name": "io.github.example/weather",
+		"description": "Weather server",
+		"version": "1.0.0",
+		"repository": {"url": "https://github.com/example/weather", "source": "github"},
+		"packages": [
+			{
+				"registryType": "oci",
+				"identifier": "docker.io/example/weather",
+				"version": "1.0.0",
+				"transport": {"type": "stdio"},
+				"environmentVariables": [
+					{"name": "WEATHER_API_URL", "default": "https://api.weather.example.com/v1"},
+					{"name": "PROXY_URL", "value": "https://{proxy_host}/"}
+				]
+			}
+		]
+	}
+}`
+
+func TestNetworkPolicyAllowHosts(t *testing.T) {
+	policy := &NetworkPolicy{
+		Publishers: map[string][]string{
+			"io.github.example": {"tiles.example.com", "metrics.example.com:8443"},
+		},
+	}
+
+	result, err := TransformJSONWithOptions(egressRegistryJSON, TransformOptions{NetworkPolicy: policy})
+	if err != nil {
+		t.Fatalf("TransformJSONWithOptions failed: %v", err)
+	}
+
+	expected := []string{"api.weather.example.com:443", "github.com:443", "metrics.example.com:8443", "tiles.example.com:443"}
+	if strings.Join(result.Server.AllowHosts, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected allowHosts %v, got %v", expected, result.Server.AllowHosts)
+	}
+	if result.Server.DisableNetwork {
+		t.Error("Expected network to stay enabled")
+	}
+
+	// Every allowed host is justified
+	if result.Egress == nil || len(result.Egress.AllowHosts) != len(expected) {
+		t.Fatalf("Expected a justification per host, got %+v", result.Egress)
+	}
+	for _, host := range result.Egress.AllowHosts {
+		if len(host.Reasons) == 0 {
+			t.Errorf("Expected reasons for %s", host.Host)
+		}
+	}
+	justifications := strings.Join(result.Egress.Justifications(), "\n")
+	if !strings.Contains(justifications, "github.com:443 (repository https://github.com/example/weather)") {
+		t.Errorf("Unexpected justifications:\n%s", justifications)
+	}
+}
+
+func TestNetworkPolicyOffline(t *testing.T) {
+	policy := &NetworkPolicy{Offline: []string{"io.github.example/weather"}}
+
+	result, err := TransformJSONWithOptions(egressRegistryJSON, TransformOptions{NetworkPolicy: policy})
+	if err != nil {
+		t.Fatalf("TransformJSONWithOptions failed: %v", err)
+	}
+
+	if !result.Server.DisableNetwork {
+		t.Error("Expected offline server to have network disabled")
+	}
+	if len(result.Server.AllowHosts) > 0 {
+		t.Errorf("Expected no allowHosts for offline server, got %v", result.Server.AllowHosts)
+	}
+}
+
+func TestNetworkPolicyNoHosts(t *testing.T) {
+	registryJSON := `{
+		"server": {
+			"name": "io.github.example/clock",
+			"description": "Clock server",
+			"version": "1.0.0",
+			"packages": [{"registryType": "oci", "identifier": "docker.io/example/clock", "version": "1.0.0", "transport": {"type": "stdio"}}]
+		}
+	}`
+
+	result, err := TransformJSONWithOptions(registryJSON, TransformOptions{NetworkPolicy: &NetworkPolicy{}})
+	if err != nil {
+		t.Fatalf("TransformJSONWithOptions failed: %v", err)
+	}
+
+	// An empty allowHosts is unrestricted, so the server gets no network
+	if !result.Server.DisableNetwork || len(result.Server.AllowHosts) > 0 {
+		t.Errorf("Expected network disabled, got %+v", result.Server)
+	}
+	if justifications := result.Egress.Justifications(); len(justifications) != 1 || !strings.Contains(justifications[0], "no declared URL") {
+		t.Errorf("Unexpected justifications %v", justifications)
+	}
+}
+
+func TestNetworkPolicyIgnoresRemotes(t *testing.T) {
+	registryJSON := `{
+		"server": {
+			"name": "com.docker/grafana-internal",
+			"description": "Internal Grafana MCP server",
+			"version": "0.1.0",
+			"remotes": [{"type": "streamable-http", "url": "https://mcp-grafana.s.us-east-1.aws.dckr.io/mcp"}]
+		}
+	}`
+
+	result, err := TransformJSONWithOptions(registryJSON, TransformOptions{NetworkPolicy: &NetworkPolicy{}})
+	if err != nil {
+		t.Fatalf("TransformJSONWithOptions failed: %v", err)
+	}
+	if len(result.Server.AllowHosts) > 0 || result.Server.DisableNetwork {
+		t.Errorf("Expected no network restrictions on remote servers, got %+v", result.Server)
+	}
+}
@@ -0,0 +1,197 @@
+package catalogs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+// ProtectedResourceMetadata is the OAuth 2.0 Protected Resource Metadata
+// document served by a remote MCP server (RFC 9728).
+type ProtectedResourceMetadata struct {
+	Resource               string   `json:"resource"`
+	AuthorizationServers   []string `json:"authorization_servers,omitempty"`
+	ScopesSupported        []string `json:"scopes_supported,omitempty"`
+	BearerMethodsSupported []string `json:"bearer_methods_supported,omitempty"`
+	ResourceName           string   `json:"resource_name,omitempty"`
+}
+
+// AuthorizationServerMetadata is the OAuth 2.0 Authorization Server Metadata
+// document (RFC 8414). OpenID Connect discovery documents share these fields.
+type AuthorizationServerMetadata struct {
+	Issuer                        string   `json:"issuer"`
+	AuthorizationEndpoint         string   `json:"authorization_endpoint"`
+	TokenEndpoint                 string   `json:"token_endpoint"`
+	RegistrationEndpoint          string   `json:"registration_endpoint,omitempty"`
+	ScopesSupported               []string `json:"scopes_supported,omitempty"`
+	ResponseTypesSupported        []string `json:"response_types_supported,omitempty"`
+	CodeChallengeMethodsSupported []string `json:"code_challenge_methods_supported,omitempty"`
+}
+
+// OAuthDiscovery holds the metadata discovered for a remote MCP server.
+type OAuthDiscovery struct {
+	Resource            ProtectedResourceMetadata   `json:"resource"`
+	AuthorizationServer AuthorizationServerMetadata `json:"authorizationServer"`
+}
+
+// OAuthRegistration is the client registration config for a remote server,
+// mirroring the flags of `docker mcp oauth register`.
+type OAuthRegistration struct {
+	Server                string   `json:"server" yaml:"server"`
+	Provider              string   `json:"provider" yaml:"provider"`
+	AuthorizationEndpoint string   `json:"authorizationEndpoint" yaml:"authorizationEndpoint"`
+	TokenEndpoint         string   `json:"tokenEndpoint" yaml:"tokenEndpoint"`
+	RegistrationEndpoint  string   `json:"registrationEndpoint,omitempty" yaml:"registrationEndpoint,omitempty"`
+	Scopes                []string `json:"scopes,omitempty" yaml:"scopes,omitempty"`
+	// Client credentials are references to secrets, never values.
+	ClientID     catalog.Secret `json:"clientId" yaml:"clientId"`
+	ClientSecret catalog.Secret `json:"clientSecret" yaml:"clientSecret"`
+}
+
+// protectedResourceMetadataURLs returns the candidate well-known locations for
+// a resource URL. RFC 9728 inserts the well-known segment between the host and
+// the path; servers commonly serve it at the root as well.
+func protectedResourceMetadataURLs(resourceURL string) ([]string, error) {
+	u, err := url.Parse(resourceURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote URL %q: %w", resourceURL, err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid remote URL %q: scheme and host are required", resourceURL)
+	}
+
+	base := fmt.Sprintf("%s://%s/.well-known/oauth-protected-resource", u.Scheme, u.Host)
+	path := strings.TrimSuffix(u.EscapedPath(), "/")
+	if path == "" {
+		return []string{base}, nil
+	}
+	return []string{base + path, base}, nil
+}
+
+// authorizationServerMetadataURLs returns the candidate metadata locations for
+// an issuer, trying RFC 8414 first and OpenID Connect discovery second.
+func authorizationServerMetadataURLs(issuer string) ([]string, error) {
+	u, err := url.Parse(issuer)
+	if err != nil {
+		return nil, fmt.Errorf("invalid authorization server %q: %w", issuer, err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid authorization server %q: scheme and host are required", issuer)
+	}
+
+	origin := fmt.Sprintf("%s://%s", u.Scheme, u.Host)
+	path := strings.TrimSuffix(u.EscapedPath(), "/")
+	return []string{
+		origin + "/.well-known/oauth-authorization-server" + path,
+		origin + "/.well-known/openid-configuration" + path,
+		origin + path + "/.well-known/openid-configuration",
+	}, nil
+}
+
+// fetchJSON fetches the first candidate URL that answers 200 and decodes it into v.
+func fetchJSON(ctx context.Context, client *http.Client, candidates []string, v any) (string, error) {
+	var errs []string
+	for _, candidate := range candidates {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, candidate, nil)
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Accept", "application/json")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", candidate, err))
+			continue
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", candidate, err))
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			errs = append(errs, fmt.Sprintf("%s: %s", candidate, resp.Status))
+			continue
+		}
+		if err := json.Unmarshal(body, v); err != nil {
+			return "", fmt.Errorf("failed to parse %s: %w", candidate, err)
+		}
+		return candidate, nil
+	}
+	return "", fmt.Errorf("no metadata found (%s)", strings.Join(errs, "; "))
+}
+
+// DiscoverOAuth queries a remote MCP server's protected resource metadata and
+// the metadata of its first authorization server.
+func DiscoverOAuth(ctx context.Context, client *http.Client, remoteURL string) (*OAuthDiscovery, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	candidates, err := protectedResourceMetadataURLs(remoteURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var discovery OAuthDiscovery
+	if _, err := fetchJSON(ctx, client, candidates, &discovery.Resource); err != nil {
+		return nil, fmt.Errorf("failed to discover protected resource metadata: %w", err)
+	}
+	if len(discovery.Resource.AuthorizationServers) == 0 {
+		return nil, fmt.Errorf("protected resource metadata for %s lists no authorization servers", remoteURL)
+	}
+
+	issuer := discovery.Resource.AuthorizationServers[0]
+	candidates, err = authorizationServerMetadataURLs(issuer)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := fetchJSON(ctx, client, candidates, &discovery.AuthorizationServer); err != nil {
+		return nil, fmt.Errorf("failed to discover authorization server metadata for %s: %w", issuer, err)
+	}
+	if discovery.AuthorizationServer.AuthorizationEndpoint == "" || discovery.AuthorizationServer.TokenEndpoint == "" {
+		return nil, fmt.Errorf("authorization server %s does not advertise authorization and token endpoints", issuer)
+	}
+
+	return &discovery, nil
+}
+
+// Scopes returns the scopes advertised by the protected resource. The
+// authorization server's list is not used since it covers every API it serves.
+func (d *OAuthDiscovery) Scopes() []string {
+	return d.Resource.ScopesSupported
+}
+
+// CatalogOAuth builds the catalog OAuth block for a provider.
+func (d *OAuthDiscovery) CatalogOAuth(provider string) *catalog.OAuth {
+	return &catalog.OAuth{
+		Providers: []catalog.OAuthProvider{
+			{
+				Provider: provider,
+				Secret:   fmt.Sprintf("%s.access_token", provider),
+				Env:      "ACCESS_TOKEN",
+			},
+		},
+		Scopes: d.Scopes(),
+	}
+}
+
+// Registration builds the client registration config for a catalog server.
+func (d *OAuthDiscovery) Registration(serverName, provider string) OAuthRegistration {
+	return OAuthRegistration{
+		Server:                serverName,
+		Provider:              provider,
+		AuthorizationEndpoint: d.AuthorizationServer.AuthorizationEndpoint,
+		TokenEndpoint:         d.AuthorizationServer.TokenEndpoint,
+		RegistrationEndpoint:  d.AuthorizationServer.RegistrationEndpoint,
+		Scopes:                d.Scopes(),
+		ClientID:              clientCredentialRef(serverName, "client_id"),
+		ClientSecret:          clientCredentialRef(serverName, "client_secret"),
+	}
+}
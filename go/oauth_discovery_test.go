@@ -0,0 +1,89 @@
+package catalogs
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newOAuthTestServer(t *testing.T, resourcePath string) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("/.well-known/oauth-protected-resource"+resourcePath, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"resource":              server.URL + "/mcp",
+			"authorization_servers": []string{server.URL + "/issuer"},
+			"scopes_supported":      []string{"https://www.googleapis.com/auth/bigquery"},
+		})
+	})
+	mux.HandleFunc("/.well-known/openid-configuration/issuer", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"issuer":                 server.URL + "/issuer",
+			"authorization_endpoint": server.URL + "/o/oauth2/v2/auth",
+			"token_endpoint":         server.URL + "/token",
+			"scopes_supported":       []string{"openid", "email"},
+		})
+	})
+
+	return server
+}
+
+func TestDiscoverOAuth(t *testing.T) {
+	server := newOAuthTestServer(t, "/mcp")
+
+	discovery, err := DiscoverOAuth(context.Background(), server.Client(), server.URL+"/mcp")
+	if err != nil {
+		t.Fatalf("DiscoverOAuth failed: %v", err)
+	}
+
+	if discovery.AuthorizationServer.AuthorizationEndpoint != server.URL+"/o/oauth2/v2/auth" {
+		t.Errorf("Unexpected authorization endpoint: %s", discovery.AuthorizationServer.AuthorizationEndpoint)
+	}
+	if discovery.AuthorizationServer.TokenEndpoint != server.URL+"/token" {
+		t.Errorf("Unexpected token endpoint: %s", discovery.AuthorizationServer.TokenEndpoint)
+	}
+
+	oauth := discovery.CatalogOAuth("google")
+	if len(oauth.Providers) != 1 || oauth.Providers[0].Secret != "google.access_token" || oauth.Providers[0].Env != "ACCESS_TOKEN" {
+		t.Errorf("Unexpected OAuth providers: %+v", oauth.Providers)
+	}
+	if len(oauth.Scopes) != 1 || oauth.Scopes[0] != "https://www.googleapis.com/auth/bigquery" {
+		t.Errorf("Expected resource scopes, got %v", oauth.Scopes)
+	}
+
+	registration := discovery.Registration("com-google-cloud-bigquery-mcp", "google")
+	if registration.Server != "com-google-cloud-bigquery-mcp" {
+		t.Errorf("Unexpected registration server: %s", registration.Server)
+	}
+	if registration.TokenEndpoint != server.URL+"/token" {
+		t.Errorf("Unexpected registration token endpoint: %s", registration.TokenEndpoint)
+	}
+}
+
+func TestDiscoverOAuthRootMetadata(t *testing.T) {
+	// Protected resource metadata served only at the root well-known location
+	server := newOAuthTestServer(t, "")
+
+	discovery, err := DiscoverOAuth(context.Background(), server.Client(), server.URL+"/mcp")
+	if err != nil {
+		t.Fatalf("DiscoverOAuth failed: %v", err)
+	}
+	if discovery.Resource.Resource != server.URL+"/mcp" {
+		t.Errorf("Unexpected resource: %s", discovery.Resource.Resource)
+	}
+}
+
+func TestDiscoverOAuthNotProtected(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	if _, err := DiscoverOAuth(context.Background(), server.Client(), server.URL+"/mcp"); err == nil {
+		t.Error("Expected error for server without protected resource metadata")
+	}
+}
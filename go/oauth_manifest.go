@@ -0,0 +1,126 @@
+package catalogs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"gopkg.in/yaml.v3"
+)
+
+// OAuthManifestFilename is the registration manifest written next to each
+// catalog/<server>/server.yaml.
+const OAuthManifestFilename = "oauth.yaml"
+
+// clientCredentialRef references a client credential. The env is prefixed
+// with the server name like the secret name, so the credentials of several
+// servers can be exported in one shell.
+func clientCredentialRef(serverName, name string) catalog.Secret {
+	secret := fmt.Sprintf("%s.%s", serverName, name)
+	return catalog.Secret{Name: secret, Env: envName(secret)}
+}
+
+func remoteURL(server catalog.Server) string {
+	if server.Remote.URL != "" {
+		return server.Remote.URL
+	}
+	return server.SSEEndpoint
+}
+
+// BuildOAuthRegistration discovers the endpoints of an OAuth catalog server
+// and returns its registration manifest. Scopes declared in the catalog entry
+// take precedence over the discovered ones.
+func BuildOAuthRegistration(ctx context.Context, client *http.Client, server catalog.Server) (*OAuthRegistration, error) {
+	if !server.IsOAuthServer() {
+		return nil, fmt.Errorf("server %s has no oauth providers", server.Name)
+	}
+	url := remoteURL(server)
+	if url == "" {
+		return nil, fmt.Errorf("server %s has no remote URL to discover OAuth metadata from", server.Name)
+	}
+
+	discovery, err := DiscoverOAuth(ctx, client, url)
+	if err != nil {
+		return nil, fmt.Errorf("server %s: %w", server.Name, err)
+	}
+
+	registration := discovery.Registration(server.Name, server.OAuth.Providers[0].Provider)
+	if len(server.OAuth.Scopes) > 0 {
+		registration.Scopes = server.OAuth.Scopes
+	}
+	return &registration, nil
+}
+
+// BuildOAuthRegistrations builds a registration manifest for every catalog
+// server with oauth providers. A positive timeout applies to each server's
+// discovery on its own, so a slow server does not use up the others' time.
+// Servers that fail are reported in errs by name.
+func BuildOAuthRegistrations(ctx context.Context, client *http.Client, servers map[string]catalog.Server, timeout time.Duration) (registrations []OAuthRegistration, errs map[string]error) {
+	errs = make(map[string]error)
+
+	for _, name := range SortedServerNames(servers) {
+		server := servers[name]
+		if !server.IsOAuthServer() {
+			continue
+		}
+		serverCtx, cancel := ctx, context.CancelFunc(func() {})
+		if timeout > 0 {
+			serverCtx, cancel = context.WithTimeout(ctx, timeout)
+		}
+		registration, err := BuildOAuthRegistration(serverCtx, client, server)
+		cancel()
+		if err != nil {
+			errs[name] = err
+			continue
+		}
+		registrations = append(registrations, *registration)
+	}
+
+	return registrations, errs
+}
+
+// MarshalOAuthRegistration renders a registration manifest as YAML, indented
+// like the catalog's server.yaml files.
+func MarshalOAuthRegistration(registration OAuthRegistration) ([]byte, error) {
+	return marshalYAML(registration)
+}
+
+// LoadOAuthRegistration reads a registration manifest such as
+// catalog/<server>/oauth.yaml
+func LoadOAuthRegistration(file string) (*OAuthRegistration, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OAuth manifest: %w", err)
+	}
+	var registration OAuthRegistration
+	if err := yaml.Unmarshal(data, &registration); err != nil {
+		return nil, fmt.Errorf("failed to parse OAuth manifest %s: %w", file, err)
+	}
+	return &registration, nil
+}
+
+// OAuthRegisterCommand renders the docker mcp oauth register command for a
+// registration manifest. The client credentials stay references to their env
+// variables, expanded by the shell that runs the command.
+func OAuthRegisterCommand(registration OAuthRegistration) (string, error) {
+	if registration.Server == "" || registration.AuthorizationEndpoint == "" || registration.TokenEndpoint == "" {
+		return "", fmt.Errorf("OAuth manifest needs a server, authorizationEndpoint and tokenEndpoint")
+	}
+	if registration.ClientID.Env == "" || registration.ClientSecret.Env == "" {
+		return "", fmt.Errorf("OAuth manifest for %s has no clientId or clientSecret env", registration.Server)
+	}
+	args := []string{"docker", "mcp", "oauth", "register", shellQuote(registration.Server),
+		"--client-id", `"$` + registration.ClientID.Env + `"`,
+		"--client-secret", `"$` + registration.ClientSecret.Env + `"`,
+		"--auth-endpoint", shellQuote(registration.AuthorizationEndpoint),
+		"--token-endpoint", shellQuote(registration.TokenEndpoint),
+	}
+	if len(registration.Scopes) > 0 {
+		args = append(args, "--scopes", shellQuote(strings.Join(registration.Scopes, ",")))
+	}
+	return strings.Join(args, " "), nil
+}
@@ -0,0 +1,131 @@
+package catalogs
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+func TestBuildOAuthRegistrations(t *testing.T) {
+	server := newOAuthTestServer(t, "/mcp")
+
+	servers := map[string]catalog.Server{
+		"com-google-cloud-bigquery-mcp": {
+			Name:   "com-google-cloud-bigquery-mcp",
+			Type:   "remote",
+			Remote: catalog.Remote{URL: server.URL + "/mcp", Transport: "streamable-http"},
+			OAuth: &catalog.OAuth{
+				Providers: []catalog.OAuthProvider{{Provider: "google", Secret: "google.access_token", Env: "ACCESS_TOKEN"}},
+			},
+		},
+		"com-docker-grafana-internal": {
+			Name:   "com-docker-grafana-internal",
+			Type:   "remote",
+			Remote: catalog.Remote{URL: server.URL + "/grafana"},
+		},
+		"com-example-image-oauth": {
+			Name:  "com-example-image-oauth",
+			Type:  "server",
+			Image: "example/image@sha256:abc",
+			OAuth: &catalog.OAuth{Providers: []catalog.OAuthProvider{{Provider: "example"}}},
+		},
+	}
+
+	registrations, errs := BuildOAuthRegistrations(context.Background(), server.Client(), servers, time.Minute)
+
+	if len(registrations) != 1 {
+		t.Fatalf("Expected 1 registration, got %d", len(registrations))
+	}
+	if _, ok := errs["com-example-image-oauth"]; !ok || len(errs) != 1 {
+		t.Errorf("Expected an error only for the image server, got %v", errs)
+	}
+
+	registration := registrations[0]
+	if registration.Provider != "google" {
+		t.Errorf("Expected provider 'google', got '%s'", registration.Provider)
+	}
+	if registration.ClientSecret.Name != "com-google-cloud-bigquery-mcp.client_secret" || registration.ClientSecret.Env != "COM_GOOGLE_CLOUD_BIGQUERY_MCP_CLIENT_SECRET" {
+		t.Errorf("Unexpected client secret reference: %+v", registration.ClientSecret)
+	}
+
+	manifest, err := MarshalOAuthRegistration(registration)
+	if err != nil {
+		t.Fatalf("MarshalOAuthRegistration failed: %v", err)
+	}
+	for _, expected := range []string{"tokenEndpoint: " + server.URL + "/token", "name: com-google-cloud-bigquery-mcp.client_id"} {
+		if !strings.Contains(string(manifest), expected) {
+			t.Errorf("Expected manifest to contain %q:\n%s", expected, manifest)
+		}
+	}
+}
+
+func TestBuildOAuthRegistrationCatalogScopes(t *testing.T) {
+	server := newOAuthTestServer(t, "/mcp")
+
+	registration, err := BuildOAuthRegistration(context.Background(), server.Client(), catalog.Server{
+		Name:   "com-google-cloud-bigquery-mcp",
+		Type:   "remote",
+		Remote: catalog.Remote{URL: server.URL + "/mcp"},
+		OAuth: &catalog.OAuth{
+			Providers: []catalog.OAuthProvider{{Provider: "google"}},
+			Scopes:    []string{"https://www.googleapis.com/auth/bigquery.readonly"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("BuildOAuthRegistration failed: %v", err)
+	}
+	if len(registration.Scopes) != 1 || registration.Scopes[0] != "https://www.googleapis.com/auth/bigquery.readonly" {
+		t.Errorf("Expected catalog scopes to take precedence, got %v", registration.Scopes)
+	}
+}
+
+func TestOAuthRegisterCommand(t *testing.T) {
+	registration, err := LoadOAuthRegistration("../catalog/com-google-cloud-bigquery-mcp/oauth.yaml")
+	if err != nil {
+		t.Fatalf("LoadOAuthRegistration failed: %v", err)
+	}
+	command, err := OAuthRegisterCommand(*registration)
+	if err != nil {
+		t.Fatalf("OAuthRegisterCommand failed: %v", err)
+	}
+	expected := `docker mcp oauth register com-google-cloud-bigquery-mcp --client-id "$COM_GOOGLE_CLOUD_BIGQUERY_MCP_CLIENT_ID"` +
+		` --client-secret "$COM_GOOGLE_CLOUD_BIGQUERY_MCP_CLIENT_SECRET"` +
+		" --auth-endpoint https://accounts.google.com/o/oauth2/v2/auth --token-endpoint https://oauth2.googleapis.com/token" +
+		" --scopes https://www.googleapis.com/auth/bigquery"
+	if command != expected {
+		t.Errorf("Unexpected command\n got: %s\nwant: %s", command, expected)
+	}
+
+	if _, err := OAuthRegisterCommand(OAuthRegistration{Server: "x", AuthorizationEndpoint: "a", TokenEndpoint: "t"}); err == nil {
+		t.Error("Expected error for a manifest without client credential references")
+	}
+}
+
+func TestBuildOAuthRegistrationsTimeoutPerServer(t *testing.T) {
+	fast := newOAuthTestServer(t, "/mcp")
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer slow.Close()
+
+	oauth := &catalog.OAuth{Providers: []catalog.OAuthProvider{{Provider: "google"}}}
+	servers := map[string]catalog.Server{
+		"a-slow": {Name: "a-slow", Type: "remote", Remote: catalog.Remote{URL: slow.URL + "/mcp"}, OAuth: oauth},
+		"b-fast": {Name: "b-fast", Type: "remote", Remote: catalog.Remote{URL: fast.URL + "/mcp"}, OAuth: oauth},
+	}
+
+	// The slow server runs first and times out without taking the fast
+	// server's time
+	registrations, errs := BuildOAuthRegistrations(context.Background(), http.DefaultClient, servers, 200*time.Millisecond)
+	if len(registrations) != 1 || registrations[0].Server != "b-fast" {
+		t.Errorf("Expected the fast server to register, got %+v", registrations)
+	}
+	if len(errs) != 1 || errs["a-slow"] == nil {
+		t.Errorf("Expected the slow server to time out, got %v", errs)
+	}
+}
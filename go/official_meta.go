@@ -0,0 +1,98 @@
+package catalogs
+
+import (
+	"encoding/json"
+	"fmt"
+
+	v0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// StatusMode decides what happens to servers the registry marks deprecated
+// or deleted
+type StatusMode string
+
+const (
+	// StatusWarn converts inactive servers and reports them. It is the zero value.
+	StatusWarn StatusMode = "warn"
+	StatusSkip StatusMode = "skip"
+)
+
+// ParseStatusMode parses a -status flag value
+func ParseStatusMode(s string) (StatusMode, error) {
+	switch mode := StatusMode(s); mode {
+	case StatusWarn, StatusSkip:
+		return mode, nil
+	}
+	return "", fmt.Errorf("invalid status mode %q (expected warn or skip)", s)
+}
+
+// InactiveServerError is returned when StatusSkip meets a deprecated or
+// deleted server
+type InactiveServerError struct {
+	Server  string
+	Version string
+	Status  model.Status
+}
+
+func (e *InactiveServerError) Error() string {
+	return fmt.Sprintf("server %s %s is %s in the registry", e.Server, e.Version, e.Status)
+}
+
+// embeddedOfficialMeta finds official metadata stored under server._meta,
+// where older registry responses (and the files in servers/) keep it
+type embeddedOfficialMeta struct {
+	Server struct {
+		Meta struct {
+			Official *v0.RegistryExtensions `json:"io.modelcontextprotocol.registry/official"`
+		} `json:"_meta"`
+	} `json:"server"`
+}
+
+// parseServerResponse parses one registry entry, falling back to official
+// metadata embedded in the server
+func parseServerResponse(data []byte) (v0.ServerResponse, error) {
+	var response v0.ServerResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return response, err
+	}
+	if response.Meta.Official == nil {
+		var embedded embeddedOfficialMeta
+		if err := json.Unmarshal(data, &embedded); err == nil {
+			response.Meta.Official = embedded.Server.Meta.Official
+		}
+	}
+	return response, nil
+}
+
+// parseServerListResponse parses a registry list response like parseServerResponse
+func parseServerListResponse(data []byte) (v0.ServerListResponse, error) {
+	var list v0.ServerListResponse
+	if err := json.Unmarshal(data, &list); err != nil {
+		return list, err
+	}
+	var embedded struct {
+		Servers []embeddedOfficialMeta `json:"servers"`
+	}
+	if err := json.Unmarshal(data, &embedded); err == nil && len(embedded.Servers) == len(list.Servers) {
+		for i := range list.Servers {
+			if list.Servers[i].Meta.Official == nil {
+				list.Servers[i].Meta.Official = embedded.Servers[i].Server.Meta.Official
+			}
+		}
+	}
+	return list, nil
+}
+
+// checkStatus reports or rejects servers that are no longer active
+func checkStatus(mode StatusMode, response v0.ServerResponse) ([]string, error) {
+	official := response.Meta.Official
+	if official == nil || official.Status == "" || official.Status == model.StatusActive {
+		return nil, nil
+	}
+	inactive := &InactiveServerError{Server: response.Server.Name, Version: response.Server.Version, Status: official.Status}
+	if mode == StatusSkip {
+		return nil, inactive
+	}
+	return []string{inactive.Error()}, nil
+}
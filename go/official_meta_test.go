@@ -0,0 +1,84 @@
+package catalogs
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestTransformOfficialMeta(t *testing.T) {
+	data, err := os.ReadFile("../servers/grounding_lite.json")
+	if err != nil {
+		t.Fatalf("Failed to read server: %v", err)
+	}
+
+	result, err := TransformJSONWithOptions(string(data), TransformOptions{})
+	if err != nil {
+		t.Fatalf("TransformJSONWithOptions failed: %v", err)
+	}
+
+	// servers/ keeps the official metadata under server._meta
+	if result.Official == nil {
+		t.Fatal("Expected official metadata")
+	}
+	if result.Official.Status != "active" || !result.Official.IsLatest || result.Official.PublishedAt.IsZero() {
+		t.Errorf("Unexpected official metadata %+v", result.Official)
+	}
+
+	// and the catalog entry carries it in its metadata
+	var entry struct {
+		Metadata EntryMetadata `json:"metadata"`
+	}
+	if err := json.Unmarshal([]byte(result.JSON), &entry); err != nil {
+		t.Fatalf("Failed to parse catalog JSON: %v", err)
+	}
+	if official := entry.Metadata.Official; official == nil || *official != *result.Official {
+		t.Errorf("Expected %+v in the catalog metadata, got %+v", result.Official, official)
+	}
+}
+
+func deprecatedRegistryJSON(status string) string {
+	return `{
+		"server": {
+			"name": "com.example/legacy",
+			"description": "Legacy server",
+			"version": "0.9.0",
+			"remotes": [{"type": "sse", "url": "https://legacy.example.com/sse"}]
+		},
+		"_meta": {
+			"io.modelcontextprotocol.registry/official": {
+				"status": "` + status + `",
+				"publishedAt": "2025-01-01T00:00:00Z",
+				"isLatest": true
+			}
+		}
+	}`
+}
+
+func TestTransformInactiveStatus(t *testing.T) {
+	// The zero value warns
+	result, err := TransformJSONWithOptions(deprecatedRegistryJSON("deprecated"), TransformOptions{})
+	if err != nil {
+		t.Fatalf("TransformJSONWithOptions failed: %v", err)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "is deprecated") {
+		t.Errorf("Expected deprecation warning, got %v", result.Warnings)
+	}
+
+	_, err = TransformJSONWithOptions(deprecatedRegistryJSON("deleted"), TransformOptions{Status: StatusSkip})
+	var inactive *InactiveServerError
+	if !errors.As(err, &inactive) || inactive.Status != "deleted" {
+		t.Errorf("Expected InactiveServerError, got %v", err)
+	}
+}
+
+func TestParseStatusMode(t *testing.T) {
+	if mode, err := ParseStatusMode("skip"); err != nil || mode != StatusSkip {
+		t.Errorf("Expected skip, got %s %v", mode, err)
+	}
+	if _, err := ParseStatusMode("ignore"); err == nil {
+		t.Error("Expected invalid mode to be rejected")
+	}
+}
@@ -0,0 +1,219 @@
+package catalogs
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+// TemplateCatalog is the data of templates rendered over a whole catalog
+type TemplateCatalog struct {
+	Name        string
+	DisplayName string
+	// Servers are sorted by name
+	Servers []catalog.Server
+}
+
+// NewTemplateCatalog sorts catalog servers for rendering
+func NewTemplateCatalog(name, displayName string, servers map[string]catalog.Server) TemplateCatalog {
+	data := TemplateCatalog{Name: name, DisplayName: displayName}
+	for _, serverName := range SortedServerNames(servers) {
+		data.Servers = append(data.Servers, servers[serverName])
+	}
+	return data
+}
+
+// TemplateConfig is one property of a server's config schema
+type TemplateConfig struct {
+	Name        string
+	Type        string
+	Description string
+	Required    bool
+	Default     any
+}
+
+// interpolationPattern matches config ({{server.key}}) and secret (${NAME})
+// references in catalog values
+var interpolationPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}|\$\{([A-Za-z0-9_]+)\}`)
+
+// interpolate replaces the references lookup knows and returns the ones it
+// does not, in order
+func interpolate(value string, lookup func(ref string) (string, bool)) (string, []string) {
+	var missing []string
+	result := interpolationPattern.ReplaceAllStringFunc(value, func(match string) string {
+		ref := interpolationRef(match)
+		if resolved, ok := lookup(ref); ok {
+			return resolved
+		}
+		missing = append(missing, match)
+		return match
+	})
+	return result, missing
+}
+
+// interpolationRef is the config key or secret env name of a reference
+func interpolationRef(match string) string {
+	groups := interpolationPattern.FindStringSubmatch(match)
+	if groups[1] != "" {
+		return groups[1]
+	}
+	return groups[2]
+}
+
+// serverConfig flattens a server's config schemas, sorted by name
+func serverConfig(server catalog.Server) []TemplateConfig {
+	var properties []TemplateConfig
+	for _, item := range server.Config {
+		schema, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		required := make(map[string]bool)
+		switch list := schema["required"].(type) {
+		case []any:
+			for _, name := range list {
+				required[fmt.Sprint(name)] = true
+			}
+		case []string:
+			for _, name := range list {
+				required[name] = true
+			}
+		}
+		props, _ := schema["properties"].(map[string]any)
+		for _, name := range sortedMapKeys(props) {
+			prop, _ := props[name].(map[string]any)
+			config := TemplateConfig{Name: name, Required: required[name], Default: prop["default"]}
+			config.Type, _ = prop["type"].(string)
+			config.Description, _ = prop["description"].(string)
+			properties = append(properties, config)
+		}
+	}
+	sort.SliceStable(properties, func(i, j int) bool { return properties[i].Name < properties[j].Name })
+	return properties
+}
+
+// TemplateFuncs are the helpers available to output templates
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"secretNames": func(server catalog.Server) []string {
+			var names []string
+			for _, secret := range server.Secrets {
+				names = append(names, secret.Name)
+			}
+			return names
+		},
+		"secretEnvs": func(server catalog.Server) []string {
+			var envs []string
+			for _, secret := range server.Secrets {
+				envs = append(envs, secret.Env)
+			}
+			return envs
+		},
+		"config": serverConfig,
+		"placeholders": func(value string) []string {
+			return interpolationPattern.FindAllString(value, -1)
+		},
+		"interpolate": func(value string, values map[string]any) string {
+			result, _ := interpolate(value, func(ref string) (string, bool) {
+				v, ok := values[ref]
+				if !ok {
+					return "", false
+				}
+				return fmt.Sprint(v), true
+			})
+			return result
+		},
+		"join": func(sep string, items []string) string {
+			return strings.Join(items, sep)
+		},
+		"upper":   strings.ToUpper,
+		"lower":   strings.ToLower,
+		"md":      markdownText,
+		"csv":     csvRow,
+		"sh":      shellQuote,
+		"json":    templateJSON,
+		"yaml":    templateYAML,
+		"default": templateDefault,
+	}
+}
+
+// markdownText escapes text for a Markdown table cell
+func markdownText(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func csvRow(fields ...any) (string, error) {
+	record := make([]string, len(fields))
+	for i, field := range fields {
+		switch f := field.(type) {
+		case []string:
+			record[i] = strings.Join(f, " ")
+		default:
+			record[i] = fmt.Sprint(f)
+		}
+	}
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(record); err != nil {
+		return "", err
+	}
+	writer.Flush()
+	return strings.TrimRight(buf.String(), "\n"), writer.Error()
+}
+
+func templateJSON(v any) (string, error) {
+	data, err := json.Marshal(v)
+	return string(data), err
+}
+
+func templateYAML(v any) (string, error) {
+	data, err := marshalYAML(v)
+	return strings.TrimRight(string(data), "\n"), err
+}
+
+func templateDefault(fallback, value any) any {
+	if value == nil {
+		return fallback
+	}
+	if s, ok := value.(string); ok && s == "" {
+		return fallback
+	}
+	return value
+}
+
+// ParseOutputTemplate parses a text/template with the output helpers
+func ParseOutputTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(TemplateFuncs()).Parse(text)
+}
+
+// LoadOutputTemplate reads an output template file
+func LoadOutputTemplate(file string) (*template.Template, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := ParseOutputTemplate(filepath.Base(file), string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", file, err)
+	}
+	return tmpl, nil
+}
+
+// RenderTemplate renders a catalog.Server or a TemplateCatalog
+func RenderTemplate(tmpl *template.Template, data any) (string, error) {
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+	return out.String(), nil
+}
@@ -0,0 +1,158 @@
+package catalogs
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+func renderTestTemplate(t *testing.T, text string, data any) string {
+	t.Helper()
+	tmpl, err := ParseOutputTemplate("test", text)
+	if err != nil {
+		t.Fatalf("ParseOutputTemplate failed: %v", err)
+	}
+	out, err := RenderTemplate(tmpl, data)
+	if err != nil {
+		t.Fatalf("RenderTemplate failed: %v", err)
+	}
+	return out
+}
+
+func TestTemplateHelpers(t *testing.T) {
+	server := catalog.Server{
+		Name:        "com-example-notes",
+		Title:       "Notes",
+		Type:        "server",
+		Description: "Notes | todos\nand more",
+		Secrets:     []catalog.Secret{{Name: "com-example-notes.api_key", Env: "API_KEY"}},
+		Env:         []catalog.Env{{Name: "REGION", Value: "{{com-example-notes.region}}"}, {Name: "TOKEN", Value: "Bearer ${API_KEY}"}},
+		Config: []any{map[string]any{
+			"name": "com-example-notes",
+			"type": "object",
+			"properties": map[string]any{
+				"region": map[string]any{"type": "string", "description": "Region"},
+				"debug":  map[string]any{"type": "boolean", "default": false},
+			},
+			"required": []any{"region"},
+		}},
+	}
+
+	tests := map[string]string{
+		`{{ secretNames . | join "," }}`:                                           "com-example-notes.api_key",
+		`{{ secretEnvs . | join "," }}`:                                            "API_KEY",
+		`{{ range config . }}{{ .Name }}:{{ .Type }}:{{ .Required }} {{ end }}`:    "debug:boolean:false region:string:true ",
+		`{{ range .Env }}{{ placeholders .Value | join "," }};{{ end }}`:           "{{com-example-notes.region}};${API_KEY};",
+		`{{ md .Description }}`:                                                    `Notes \| todos and more`,
+		`{{ csv .Name .Description (secretEnvs .) }}`:                              "com-example-notes,\"Notes | todos\nand more\",API_KEY",
+		`{{ default "n/a" .Icon }} {{ upper .Title }} {{ lower .Type }}`:           "n/a NOTES server",
+		`{{ json .Secrets }}`:                                                      `[{"name":"com-example-notes.api_key","env":"API_KEY"}]`,
+		`{{ range .Env }}{{ sh .Value }} {{ end }}{{ sh "it's" }} {{ sh .Title }}`: `'{{com-example-notes.region}}' 'Bearer ${API_KEY}' 'it'\''s' Notes`,
+	}
+	for text, expected := range tests {
+		if got := renderTestTemplate(t, text, server); got != expected {
+			t.Errorf("%s: expected %q, got %q", text, expected, got)
+		}
+	}
+}
+
+func TestTemplateInterpolate(t *testing.T) {
+	text := `{{ $values := .Values }}{{ range .Server.Env }}{{ .Name }}={{ interpolate .Value $values }} {{ end }}`
+	data := map[string]any{
+		"Server": catalog.Server{
+			Name: "com-example-notes",
+			Env:  []catalog.Env{{Name: "REGION", Value: "{{com-example-notes.region}}"}, {Name: "TOKEN", Value: "Bearer ${API_KEY}"}},
+		},
+		"Values": map[string]any{"com-example-notes.region": "eu-west-1"},
+	}
+
+	expected := "REGION=eu-west-1 TOKEN=Bearer ${API_KEY} "
+	if got := renderTestTemplate(t, text, data); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
+
+func TestInterpolateMissing(t *testing.T) {
+	result, missing := interpolate("{{ a.b }}-${C}-{{d}}", func(ref string) (string, bool) {
+		return "x", ref == "a.b"
+	})
+	if result != "x-${C}-{{d}}" {
+		t.Errorf("Unexpected result %q", result)
+	}
+	if !reflect.DeepEqual(missing, []string{"${C}", "{{d}}"}) {
+		t.Errorf("Unexpected missing %v", missing)
+	}
+}
+
+func TestTemplateCatalog(t *testing.T) {
+	servers := map[string]catalog.Server{
+		"b": {Name: "b", Type: "remote"},
+		"a": {Name: "a", Type: "server"},
+	}
+	data := NewTemplateCatalog("docker-mcp", "Docker MCP", servers)
+
+	out := renderTestTemplate(t, `{{ .Name }}:{{ range .Servers }} {{ .Name }}{{ end }}`, data)
+	if out != "docker-mcp: a b" {
+		t.Errorf("Unexpected output %q", out)
+	}
+}
+
+func TestBundledTemplates(t *testing.T) {
+	files, err := filepath.Glob("templates/*.tmpl")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("Expected bundled templates, got %v (%v)", files, err)
+	}
+
+	server := catalog.Server{
+		Name:        "com-example-notes",
+		Title:       "Notes",
+		Type:        "server",
+		Description: "Notes | todos\nand more",
+		Secrets:     []catalog.Secret{{Name: "com-example-notes.api_key", Env: "API_KEY"}},
+		Env: []catalog.Env{
+			{Name: "REGION", Value: "{{com-example-notes.region}}"},
+			{Name: "TOKEN", Value: "Bearer ${API_KEY}"},
+			{Name: "LOG_LEVEL", Value: "info"},
+		},
+		Config: []any{map[string]any{
+			"name": "com-example-notes",
+			"type": "object",
+			"properties": map[string]any{
+				"region": map[string]any{"type": "string", "description": "Region"},
+				"debug":  map[string]any{"type": "boolean", "default": false},
+			},
+			"required": []any{"region"},
+		}},
+	}
+	data := NewTemplateCatalog("", "", map[string]catalog.Server{server.Name: server})
+	for _, file := range files {
+		tmpl, err := LoadOutputTemplate(file)
+		if err != nil {
+			t.Fatalf("LoadOutputTemplate failed: %v", err)
+		}
+		if filepath.Base(file) != "env.sh.tmpl" {
+			if _, err := RenderTemplate(tmpl, data); err != nil {
+				t.Errorf("%s: %v", file, err)
+			}
+			continue
+		}
+
+		// Values that need config or secrets are left commented out
+		// instead of exporting the placeholders
+		out, err := RenderTemplate(tmpl, server)
+		if err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		expected := `# com-example-notes
+export API_KEY=
+# export REGION=  (needs {{com-example-notes.region}})
+# export TOKEN=  (needs ${API_KEY})
+export LOG_LEVEL=info
+`
+		if out != expected {
+			t.Errorf("Unexpected env.sh:\n%s", out)
+		}
+	}
+}
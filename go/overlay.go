@@ -0,0 +1,397 @@
+package catalogs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"gopkg.in/yaml.v3"
+)
+
+// PatchOperation is one RFC 6902 JSON Patch operation
+type PatchOperation struct {
+	Op    string `json:"op" yaml:"op"`
+	Path  string `json:"path" yaml:"path"`
+	From  string `json:"from,omitempty" yaml:"from,omitempty"`
+	Value any    `json:"value,omitempty" yaml:"value,omitempty"`
+}
+
+// Overlay is a server-specific fix applied after the transform, read from
+// overlays/<server-name>.yaml. The file holds either a strategic merge
+// document (a mapping merged into the catalog server) or a JSON Patch (a
+// list of operations).
+type Overlay struct {
+	Server string
+	Source string
+	// Merge is merged into the server: mappings recursively, lists of
+	// objects with a name by name, null deletes, anything else replaces
+	Merge map[string]any
+	Patch []PatchOperation
+}
+
+// ParseOverlay parses an overlay document for a server
+func ParseOverlay(server string, data []byte) (*Overlay, error) {
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	overlay := &Overlay{Server: server}
+	switch doc.(type) {
+	case map[string]any:
+		if err := yaml.Unmarshal(data, &overlay.Merge); err != nil {
+			return nil, err
+		}
+	case []any:
+		if err := yaml.Unmarshal(data, &overlay.Patch); err != nil {
+			return nil, err
+		}
+		for _, op := range overlay.Patch {
+			switch op.Op {
+			case "add", "remove", "replace", "move", "copy", "test":
+			default:
+				return nil, fmt.Errorf("unsupported JSON Patch operation %q", op.Op)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("overlay must be a mapping (strategic merge) or a list (JSON Patch)")
+	}
+	return overlay, nil
+}
+
+// LoadOverlays reads <server-name>.yaml (or .yml, .json) overlays from a
+// directory, keyed by server name
+func LoadOverlays(dir string) (map[string]*Overlay, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	overlays := make(map[string]*Overlay)
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml" && ext != ".json") {
+			continue
+		}
+		file := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		server := strings.TrimSuffix(entry.Name(), ext)
+		overlay, err := ParseOverlay(server, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse overlay %s: %w", file, err)
+		}
+		overlay.Source = file
+		overlays[server] = overlay
+	}
+	return overlays, nil
+}
+
+// Apply applies the overlay to a catalog server. Patch operations on paths
+// that no longer exist, and merged fields the catalog does not know, are
+// errors.
+func (o *Overlay) Apply(server *catalog.Server) error {
+	data, err := json.Marshal(server)
+	if err != nil {
+		return err
+	}
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	if o.Merge != nil {
+		doc = strategicMerge(doc, jsonCompatible(o.Merge))
+	}
+	for _, op := range o.Patch {
+		if doc, err = applyPatchOperation(doc, op); err != nil {
+			return fmt.Errorf("%s %s: %w", op.Op, op.Path, err)
+		}
+	}
+
+	data, err = json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	var patched catalog.Server
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&patched); err != nil {
+		return fmt.Errorf("overlay does not produce a valid catalog server: %w", err)
+	}
+	*server = patched
+	return nil
+}
+
+// jsonCompatible converts YAML-decoded values to what encoding/json produces
+func jsonCompatible(v any) any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return v
+	}
+	return out
+}
+
+func strategicMerge(target, patch any) any {
+	switch p := patch.(type) {
+	case map[string]any:
+		t, ok := target.(map[string]any)
+		if !ok {
+			t = make(map[string]any)
+		}
+		for key, value := range p {
+			if value == nil {
+				delete(t, key)
+				continue
+			}
+			t[key] = strategicMerge(t[key], value)
+		}
+		return t
+	case []any:
+		t, ok := target.([]any)
+		if !ok || !namedList(p) || !namedList(t) {
+			return p
+		}
+		index := make(map[string]int, len(t))
+		for i, item := range t {
+			index[item.(map[string]any)["name"].(string)] = i
+		}
+		for _, item := range p {
+			name := item.(map[string]any)["name"].(string)
+			if i, ok := index[name]; ok {
+				t[i] = strategicMerge(t[i], item)
+			} else {
+				t = append(t, item)
+			}
+		}
+		return t
+	}
+	return patch
+}
+
+// namedList reports whether every item is an object with a string name
+func namedList(items []any) bool {
+	for _, item := range items {
+		m, ok := item.(map[string]any)
+		if !ok {
+			return false
+		}
+		if _, ok := m["name"].(string); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// splitPointer splits an RFC 6901 JSON pointer
+func splitPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("path must start with /")
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// pointerGet returns the value at a pointer
+func pointerGet(doc any, pointer string) (any, error) {
+	tokens, err := splitPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	current := doc
+	for _, token := range tokens {
+		switch c := current.(type) {
+		case map[string]any:
+			value, ok := c[token]
+			if !ok {
+				return nil, fmt.Errorf("path not found")
+			}
+			current = value
+		case []any:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(c) {
+				return nil, fmt.Errorf("path not found")
+			}
+			current = c[i]
+		default:
+			return nil, fmt.Errorf("path not found")
+		}
+	}
+	return current, nil
+}
+
+// pointerUpdate changes the parent of the last token with update, which
+// gets the parent container and returns its replacement
+func pointerUpdate(doc any, tokens []string, update func(parent any, last string) (any, error)) (any, error) {
+	if len(tokens) == 1 {
+		return update(doc, tokens[0])
+	}
+	switch c := doc.(type) {
+	case map[string]any:
+		child, ok := c[tokens[0]]
+		if !ok {
+			return nil, fmt.Errorf("path not found")
+		}
+		updated, err := pointerUpdate(child, tokens[1:], update)
+		if err != nil {
+			return nil, err
+		}
+		c[tokens[0]] = updated
+		return c, nil
+	case []any:
+		i, err := strconv.Atoi(tokens[0])
+		if err != nil || i < 0 || i >= len(c) {
+			return nil, fmt.Errorf("path not found")
+		}
+		updated, err := pointerUpdate(c[i], tokens[1:], update)
+		if err != nil {
+			return nil, err
+		}
+		c[i] = updated
+		return c, nil
+	}
+	return nil, fmt.Errorf("path not found")
+}
+
+func pointerAdd(doc any, pointer string, value any, replace bool) (any, error) {
+	tokens, err := splitPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	return pointerUpdate(doc, tokens, func(parent any, last string) (any, error) {
+		switch p := parent.(type) {
+		case map[string]any:
+			if _, ok := p[last]; replace && !ok {
+				return nil, fmt.Errorf("path not found")
+			}
+			p[last] = value
+			return p, nil
+		case []any:
+			if last == "-" && !replace {
+				return append(p, value), nil
+			}
+			i, err := strconv.Atoi(last)
+			if err != nil || i < 0 || i > len(p) || (replace && i == len(p)) {
+				return nil, fmt.Errorf("path not found")
+			}
+			if replace {
+				p[i] = value
+				return p, nil
+			}
+			return append(p[:i], append([]any{value}, p[i:]...)...), nil
+		}
+		return nil, fmt.Errorf("path not found")
+	})
+}
+
+func pointerRemove(doc any, pointer string) (any, error) {
+	tokens, err := splitPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("cannot remove the whole server")
+	}
+	return pointerUpdate(doc, tokens, func(parent any, last string) (any, error) {
+		switch p := parent.(type) {
+		case map[string]any:
+			if _, ok := p[last]; !ok {
+				return nil, fmt.Errorf("path not found")
+			}
+			delete(p, last)
+			return p, nil
+		case []any:
+			i, err := strconv.Atoi(last)
+			if err != nil || i < 0 || i >= len(p) {
+				return nil, fmt.Errorf("path not found")
+			}
+			return append(p[:i], p[i+1:]...), nil
+		}
+		return nil, fmt.Errorf("path not found")
+	})
+}
+
+func applyPatchOperation(doc any, op PatchOperation) (any, error) {
+	value := jsonCompatible(op.Value)
+	switch op.Op {
+	case "add":
+		return pointerAdd(doc, op.Path, value, false)
+	case "replace":
+		return pointerAdd(doc, op.Path, value, true)
+	case "remove":
+		return pointerRemove(doc, op.Path)
+	case "move", "copy":
+		moved, err := pointerGet(doc, op.From)
+		if err != nil {
+			return nil, fmt.Errorf("from %s: %w", op.From, err)
+		}
+		moved = jsonCompatible(moved)
+		if op.Op == "move" {
+			if doc, err = pointerRemove(doc, op.From); err != nil {
+				return nil, err
+			}
+		}
+		return pointerAdd(doc, op.Path, moved, false)
+	case "test":
+		actual, err := pointerGet(doc, op.Path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(actual, value) {
+			return nil, fmt.Errorf("test failed: value is %v", actual)
+		}
+		return doc, nil
+	}
+	return nil, fmt.Errorf("unsupported operation")
+}
+
+// UnusedOverlays lists overlays whose server is not in the catalog, e.g.
+// because it was renamed or removed upstream
+func UnusedOverlays(overlays map[string]*Overlay, servers map[string]catalog.Server) []string {
+	var unused []string
+	for name := range overlays {
+		if _, ok := servers[name]; !ok {
+			unused = append(unused, name)
+		}
+	}
+	sort.Strings(unused)
+	return unused
+}
+
+// ValidateOverlays applies every overlay to a copy of its server, returning
+// the problems keyed by overlay server name: a missing target server or an
+// overlay that no longer applies
+func ValidateOverlays(overlays map[string]*Overlay, servers map[string]catalog.Server) map[string]error {
+	problems := make(map[string]error)
+	for name, overlay := range overlays {
+		server, ok := servers[name]
+		if !ok {
+			problems[name] = fmt.Errorf("server %s is not in the catalog", name)
+			continue
+		}
+		if err := overlay.Apply(&server); err != nil {
+			problems[name] = err
+		}
+	}
+	return problems
+}
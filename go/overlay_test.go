@@ -0,0 +1,192 @@
+package catalogs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+func TestOverlayStrategicMerge(t *testing.T) {
+	overlay, err := ParseOverlay("com-example-notes", []byte(`
+icon: https://example.com/notes.png
+user: "1000:1000"
+volumes:
+  - /data:/data
+  - /cache:/cache
+env:
+  - name: DEBUG
+    value: "false"
+  - name: LOG_LEVEL
+    value: info
+title: null
+`))
+	if err != nil {
+		t.Fatalf("ParseOverlay failed: %v", err)
+	}
+
+	server := catalog.Server{
+		Name:    "com-example-notes",
+		Type:    "server",
+		Title:   "Notes",
+		Image:   "docker.io/example/notes:1.0.0",
+		Volumes: []string{"/data:/data"},
+		Env:     []catalog.Env{{Name: "UNITS", Value: "metric"}, {Name: "DEBUG", Value: "true"}},
+	}
+	if err := overlay.Apply(&server); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	if server.Icon != "https://example.com/notes.png" || server.User != "1000:1000" || server.Title != "" {
+		t.Errorf("Unexpected server %+v", server)
+	}
+	if len(server.Volumes) != 2 {
+		t.Errorf("Expected volumes to be replaced, got %v", server.Volumes)
+	}
+	expectedEnv := []catalog.Env{{Name: "UNITS", Value: "metric"}, {Name: "DEBUG", Value: "false"}, {Name: "LOG_LEVEL", Value: "info"}}
+	if len(server.Env) != 3 || server.Env[0] != expectedEnv[0] || server.Env[1] != expectedEnv[1] || server.Env[2] != expectedEnv[2] {
+		t.Errorf("Expected env to be merged by name, got %+v", server.Env)
+	}
+}
+
+func TestOverlayJSONPatch(t *testing.T) {
+	overlay, err := ParseOverlay("com-example-notes", []byte(`
+- op: test
+  path: /image
+  value: docker.io/example/notes:1.0.0
+- op: replace
+  path: /image
+  value: docker.io/example/notes:1.0.1
+- op: add
+  path: /volumes/-
+  value: /cache:/cache
+- op: remove
+  path: /env/1
+`))
+	if err != nil {
+		t.Fatalf("ParseOverlay failed: %v", err)
+	}
+
+	server := catalog.Server{
+		Name:    "com-example-notes",
+		Type:    "server",
+		Image:   "docker.io/example/notes:1.0.0",
+		Volumes: []string{"/data:/data"},
+		Env:     []catalog.Env{{Name: "UNITS", Value: "metric"}, {Name: "DEBUG", Value: "true"}},
+	}
+	if err := overlay.Apply(&server); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if server.Image != "docker.io/example/notes:1.0.1" || len(server.Volumes) != 2 || len(server.Env) != 1 {
+		t.Errorf("Unexpected server %+v", server)
+	}
+}
+
+func TestOverlayStaleTargets(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		overlay string
+		err     string
+	}{
+		{name: "missing path", overlay: "- op: remove\n  path: /volumes/3\n", err: "path not found"},
+		{name: "unknown field", overlay: "iconUrl: https://example.com/notes.png\n", err: "iconUrl"},
+	} {
+		overlay, err := ParseOverlay("com-example-notes", []byte(tc.overlay))
+		if err != nil {
+			t.Fatalf("%s: ParseOverlay failed: %v", tc.name, err)
+		}
+		server := catalog.Server{Name: "com-example-notes", Type: "server", Image: "docker.io/example/notes:1.0.0", Volumes: []string{"/data:/data"}}
+		if err := overlay.Apply(&server); err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%s: expected an error about %s, got %v", tc.name, tc.err, err)
+		}
+	}
+
+	if _, err := ParseOverlay("com-example-notes", []byte("- op: merge\n  path: /image\n")); err == nil {
+		t.Error("Expected an unsupported operation to be rejected")
+	}
+}
+
+func TestLoadOverlaysAndValidate(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "com-example-notes.yaml"), []byte("user: \"1000\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "com-example-renamed.yaml"), []byte("user: \"1000\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	overlays, err := LoadOverlays(dir)
+	if err != nil {
+		t.Fatalf("LoadOverlays failed: %v", err)
+	}
+	servers := map[string]catalog.Server{
+		"com-example-notes": {Name: "com-example-notes", Type: "server", Image: "docker.io/example/notes:1.0.0"},
+	}
+
+	if unused := UnusedOverlays(overlays, servers); len(unused) != 1 || unused[0] != "com-example-renamed" {
+		t.Errorf("Expected the renamed overlay to be unused, got %v", unused)
+	}
+	problems := ValidateOverlays(overlays, servers)
+	if len(problems) != 1 || problems["com-example-renamed"] == nil {
+		t.Errorf("Unexpected problems %v", problems)
+	}
+}
+
+func TestTransformWithOverlay(t *testing.T) {
+	overlay, err := ParseOverlay("com-docker-grafana-internal", []byte("icon: https://example.com/grafana.png\n"))
+	if err != nil {
+		t.Fatalf("ParseOverlay failed: %v", err)
+	}
+
+	result, err := TransformJSONWithOptions(remoteRegistryJSON("https://grafana.example.com/mcp"), TransformOptions{
+		Overlays:  map[string]*Overlay{"com-docker-grafana-internal": overlay},
+		Admission: &AdmissionPolicy{RequireIcon: true},
+	})
+	if err != nil {
+		t.Fatalf("Expected the overlay icon to satisfy admission: %v", err)
+	}
+	if result.Server.Icon != "https://example.com/grafana.png" || result.Overlay != overlay {
+		t.Errorf("Expected the overlay to be applied, got %+v", result.Server)
+	}
+}
+
+func TestOverlayAfterNetworkPolicy(t *testing.T) {
+	overlay, err := ParseOverlay("io-github-example-weather", []byte(`
+allowHosts:
+  - api.weather.example.com:443
+  - proxy.example.com:443
+`))
+	if err != nil {
+		t.Fatalf("ParseOverlay failed: %v", err)
+	}
+
+	result, err := TransformJSONWithOptions(egressRegistryJSON, TransformOptions{
+		NetworkPolicy: &NetworkPolicy{SkipRepositoryHost: true},
+		Overlays:      map[string]*Overlay{"io-github-example-weather": overlay},
+	})
+	if err != nil {
+		t.Fatalf("TransformJSONWithOptions failed: %v", err)
+	}
+	if strings.Join(result.Server.AllowHosts, ",") != "api.weather.example.com:443,proxy.example.com:443" {
+		t.Errorf("Expected the overlay allowHosts to win, got %v", result.Server.AllowHosts)
+	}
+
+	// The policy would disable the network of an offline server; the overlay
+	// turns it back on
+	overlay, err = ParseOverlay("io-github-example-weather", []byte("disableNetwork: false\n"))
+	if err != nil {
+		t.Fatalf("ParseOverlay failed: %v", err)
+	}
+	result, err = TransformJSONWithOptions(egressRegistryJSON, TransformOptions{
+		NetworkPolicy: &NetworkPolicy{Offline: []string{"io.github.example/weather"}},
+		Overlays:      map[string]*Overlay{"io-github-example-weather": overlay},
+	})
+	if err != nil {
+		t.Fatalf("TransformJSONWithOptions failed: %v", err)
+	}
+	if result.Server.DisableNetwork {
+		t.Error("Expected the overlay disableNetwork to win")
+	}
+}
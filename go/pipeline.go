@@ -0,0 +1,346 @@
+package catalogs
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// Names of the default pipeline stages, in order
+const (
+	StageIdentity  = "identity"
+	StageImage     = "image"
+	StageRemote    = "remote"
+	StageVariables = "variables"
+	StageEnv       = "env"
+	StageArgs      = "args"
+	StageMetadata  = "metadata"
+	StageOAuth     = "oauth"
+	StageIcon      = "icon"
+)
+
+// Stage is one step of the transform pipeline
+type Stage interface {
+	Name() string
+	Run(ctx *StageContext) error
+}
+
+// StageContext is shared by the stages of one transform. Stages read the
+// registry entry and what earlier stages derived, and fill in Server.
+type StageContext struct {
+	// Detail is the registry entry. The remote stage narrows Remotes to
+	// the selected remote.
+	Detail ServerDetail
+	// Transports orders remote transports by preference
+	Transports []string
+	Server     *catalog.Server
+
+	// Package is the first package, if any
+	Package *model.Package
+	// Remote is the selected remote, if any
+	Remote     *model.Transport
+	SecretVars map[string]model.Input
+	ConfigVars map[string]model.Input
+	// Publisher is the decoded publisher-provided _meta; stages read it
+	// through PublisherMeta
+	Publisher *PublisherMeta
+
+	AlternateRemotes   []catalog.Remote
+	ContainerTransport *ContainerTransport
+
+	publisherDecoded bool
+	warnings         []string
+}
+
+// Warn reports something the current stage could not convert
+func (c *StageContext) Warn(warnings ...string) {
+	c.warnings = append(c.warnings, warnings...)
+}
+
+// PublisherMeta decodes the publisher-provided _meta on first use, so each
+// stage that reads it works without the others. The stage that decodes it
+// reports the decoding warnings.
+func (c *StageContext) PublisherMeta() *PublisherMeta {
+	if c.Publisher == nil && !c.publisherDecoded {
+		c.publisherDecoded = true
+		if raw := getPublisherProvidedMeta(c.Detail.Meta); raw != nil {
+			var warnings []string
+			c.Publisher, warnings = decodePublisherMeta(raw)
+			c.Warn(warnings...)
+		}
+	}
+	return c.Publisher
+}
+
+// StageReport is the timing and diagnostics of one stage
+type StageReport struct {
+	Stage    string
+	Disabled bool
+	Duration time.Duration
+	Warnings []string
+}
+
+type funcStage struct {
+	name string
+	run  func(ctx *StageContext) error
+}
+
+func (s funcStage) Name() string                { return s.name }
+func (s funcStage) Run(ctx *StageContext) error { return s.run(ctx) }
+
+// NewStage creates a stage from a function
+func NewStage(name string, run func(ctx *StageContext) error) Stage {
+	return funcStage{name: name, run: run}
+}
+
+// Pipeline is an ordered list of stages
+type Pipeline struct {
+	stages   []Stage
+	disabled map[string]bool
+}
+
+// DefaultPipeline returns a new pipeline with the standard stages, which
+// callers may change without affecting other pipelines
+func DefaultPipeline() *Pipeline {
+	return &Pipeline{stages: []Stage{
+		NewStage(StageIdentity, identityStage),
+		NewStage(StageImage, imageStage),
+		NewStage(StageRemote, remoteStage),
+		NewStage(StageVariables, variablesStage),
+		NewStage(StageEnv, envStage),
+		NewStage(StageArgs, argsStage),
+		NewStage(StageMetadata, metadataStage),
+		NewStage(StageOAuth, oauthStage),
+		NewStage(StageIcon, iconStage),
+	}}
+}
+
+// Stages returns the stage names in order
+func (p *Pipeline) Stages() []string {
+	names := make([]string, len(p.stages))
+	for i, stage := range p.stages {
+		names[i] = stage.Name()
+	}
+	return names
+}
+
+func (p *Pipeline) index(name string) (int, error) {
+	for i, stage := range p.stages {
+		if stage.Name() == name {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("no stage named %q", name)
+}
+
+// InsertBefore adds a stage before the named stage
+func (p *Pipeline) InsertBefore(name string, stage Stage) error {
+	i, err := p.index(name)
+	if err != nil {
+		return err
+	}
+	p.stages = append(p.stages[:i], append([]Stage{stage}, p.stages[i:]...)...)
+	return nil
+}
+
+// InsertAfter adds a stage after the named stage
+func (p *Pipeline) InsertAfter(name string, stage Stage) error {
+	i, err := p.index(name)
+	if err != nil {
+		return err
+	}
+	p.stages = append(p.stages[:i+1], append([]Stage{stage}, p.stages[i+1:]...)...)
+	return nil
+}
+
+// Append adds a stage at the end
+func (p *Pipeline) Append(stage Stage) {
+	p.stages = append(p.stages, stage)
+}
+
+// Replace swaps the named stage for another
+func (p *Pipeline) Replace(name string, stage Stage) error {
+	i, err := p.index(name)
+	if err != nil {
+		return err
+	}
+	p.stages[i] = stage
+	return nil
+}
+
+// Disable skips the named stage. The identity stage names the server and
+// cannot be disabled, only replaced.
+func (p *Pipeline) Disable(name string) error {
+	if _, err := p.index(name); err != nil {
+		return err
+	}
+	if name == StageIdentity {
+		return fmt.Errorf("the %s stage cannot be disabled", StageIdentity)
+	}
+	if p.disabled == nil {
+		p.disabled = make(map[string]bool)
+	}
+	p.disabled[name] = true
+	return nil
+}
+
+// Run transforms a registry entry, reporting timing and warnings per stage
+func (p *Pipeline) Run(serverDetail ServerDetail, transports []string) (*TransformResult, error) {
+	ctx := &StageContext{
+		Detail:     serverDetail,
+		Transports: transports,
+		Server:     &catalog.Server{},
+	}
+	if len(serverDetail.Packages) > 0 {
+		ctx.Package = &ctx.Detail.Packages[0]
+	}
+
+	var reports []StageReport
+	var warnings []string
+	for _, stage := range p.stages {
+		report := StageReport{Stage: stage.Name()}
+		if p.disabled[stage.Name()] {
+			report.Disabled = true
+			reports = append(reports, report)
+			continue
+		}
+		ctx.warnings = nil
+		start := time.Now()
+		err := stage.Run(ctx)
+		report.Duration = time.Since(start)
+		if err != nil {
+			return nil, fmt.Errorf("%s stage: %w", stage.Name(), err)
+		}
+		report.Warnings = ctx.warnings
+		warnings = append(warnings, ctx.warnings...)
+		reports = append(reports, report)
+	}
+
+	return &TransformResult{
+		Server:             ctx.Server,
+		Warnings:           warnings,
+		AlternateRemotes:   ctx.AlternateRemotes,
+		ContainerTransport: ctx.ContainerTransport,
+		Publisher:          ctx.Publisher,
+		Stages:             reports,
+	}, nil
+}
+
+func identityStage(ctx *StageContext) error {
+	ctx.Server.Name = extractServerName(ctx.Detail.Name)
+	ctx.Server.Title = ctx.Detail.Title
+	ctx.Server.Description = ctx.Detail.Description
+	return nil
+}
+
+func imageStage(ctx *StageContext) error {
+	if ctx.Package == nil {
+		return nil
+	}
+	if image := extractImageInfo(*ctx.Package); image != "" {
+		ctx.Server.Image = image
+		ctx.Server.Type = "server"
+	}
+	// Containers serving HTTP keep running while the client connects
+	httpContainer, err := containerTransport(*ctx.Package)
+	if err != nil {
+		return err
+	}
+	if httpContainer != nil {
+		ctx.ContainerTransport = httpContainer
+		ctx.Server.LongLived = true
+		ctx.Warn(fmt.Sprintf("the gateway starts images over stdio, but %s serves %s on port %d; the entry keeps the port in metadata.containerTransport for deploy",
+			ctx.Package.Identifier, httpContainer.Transport, httpContainer.Port))
+	}
+	return nil
+}
+
+func remoteStage(ctx *StageContext) error {
+	selected, alternates, selectionWarnings := selectRemote(ctx.Detail.Remotes, ctx.Transports)
+	if selected < 0 {
+		return nil
+	}
+	remote := ctx.Detail.Remotes[selected]
+	ctx.Warn(selectionWarnings...)
+	alternateRemotes, alternateWarnings := convertAlternateRemotes(ctx.Detail.Remotes, alternates)
+	ctx.AlternateRemotes = alternateRemotes
+	ctx.Warn(alternateWarnings...)
+	// Only the selected remote's variables become config and secrets
+	ctx.Detail.Remotes = []model.Transport{remote}
+	ctx.Remote = &remote
+
+	remoteVal, err := convertRemote(remote)
+	if err != nil {
+		return err
+	}
+	ctx.Server.Remote = remoteVal
+	ctx.Server.Type = "remote"
+	return nil
+}
+
+func variablesStage(ctx *StageContext) error {
+	ctx.SecretVars, ctx.ConfigVars = separateSecretsAndConfig(collectVariables(ctx.Detail))
+	if len(ctx.ConfigVars) > 0 {
+		ctx.Server.Config = buildConfigSchema(ctx.ConfigVars, ctx.Server.Name)
+	}
+	if len(ctx.SecretVars) > 0 {
+		ctx.Server.Secrets = buildSecrets(ctx.Server.Name, ctx.SecretVars)
+	}
+	return nil
+}
+
+func envStage(ctx *StageContext) error {
+	if ctx.Package != nil && len(ctx.Package.EnvironmentVariables) > 0 {
+		ctx.Server.Env = convertEnvVariables(ctx.Package.EnvironmentVariables, ctx.ConfigVars, ctx.Server.Name)
+	}
+	return nil
+}
+
+func argsStage(ctx *StageContext) error {
+	if ctx.Package == nil {
+		return nil
+	}
+	// Command from package arguments
+	if len(ctx.Package.PackageArguments) > 0 {
+		ctx.Server.Command = convertPackageArgsToCommand(ctx.Package.PackageArguments)
+	}
+	// User, volumes, env and network settings from runtime arguments
+	if len(ctx.Package.RuntimeArguments) > 0 {
+		runtime := parseRuntimeArgs(ctx.Package.RuntimeArguments)
+		ctx.Server.User = runtime.User
+		ctx.Server.Volumes = runtime.Volumes
+		ctx.Server.Env = append(ctx.Server.Env, runtime.Env...)
+		ctx.Server.DisableNetwork = runtime.DisableNetwork
+		ctx.Server.ExtraHosts = runtime.ExtraHosts
+		ctx.Warn(unsupportedRuntimeArgWarnings(runtime)...)
+	}
+	return nil
+}
+
+// metadataStage copies tools, readme and metadata from the
+// publisher-provided _meta
+func metadataStage(ctx *StageContext) error {
+	if publisher := ctx.PublisherMeta(); publisher != nil {
+		applyPublisherMeta(publisher, ctx.Server)
+	}
+	return nil
+}
+
+func oauthStage(ctx *StageContext) error {
+	if publisher := ctx.PublisherMeta(); publisher != nil && publisher.OAuth != nil {
+		ctx.Server.OAuth = publisher.OAuth
+	}
+	return nil
+}
+
+// iconStage prefers the registry icons over the publisher-provided one
+func iconStage(ctx *StageContext) error {
+	if len(ctx.Detail.Icons) > 0 {
+		ctx.Server.Icon = ctx.Detail.Icons[0].Src
+	} else if publisher := ctx.PublisherMeta(); publisher != nil && publisher.Icon != "" {
+		ctx.Server.Icon = publisher.Icon
+	}
+	return nil
+}
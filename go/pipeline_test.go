@@ -0,0 +1,170 @@
+package catalogs
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDefaultPipelineStages(t *testing.T) {
+	expected := []string{StageIdentity, StageImage, StageRemote, StageVariables, StageEnv, StageArgs, StageMetadata, StageOAuth, StageIcon}
+	if got := DefaultPipeline().Stages(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected stages %v, got %v", expected, got)
+	}
+}
+
+func TestPipelineReportsStages(t *testing.T) {
+	result, err := TransformJSONWithOptions(multiRemoteRegistryJSON, TransformOptions{Transports: []string{"websocket"}})
+	if err != nil {
+		t.Fatalf("TransformJSONWithOptions failed: %v", err)
+	}
+
+	if len(result.Stages) != 9 {
+		t.Fatalf("Expected 9 stage reports, got %+v", result.Stages)
+	}
+	for _, report := range result.Stages {
+		if report.Stage == StageRemote {
+			if len(report.Warnings) != 1 || !strings.Contains(report.Warnings[0], "falling back") {
+				t.Errorf("Expected fallback warning on the remote stage, got %v", report.Warnings)
+			}
+		} else if len(report.Warnings) != 0 {
+			t.Errorf("Unexpected warnings on %s: %v", report.Stage, report.Warnings)
+		}
+	}
+}
+
+func TestPipelineInsertReplaceDisable(t *testing.T) {
+	pipeline := DefaultPipeline()
+	if err := pipeline.InsertAfter(StageIdentity, NewStage("prefix", func(ctx *StageContext) error {
+		ctx.Server.Name = "internal-" + ctx.Server.Name
+		return nil
+	})); err != nil {
+		t.Fatalf("InsertAfter failed: %v", err)
+	}
+	if err := pipeline.Replace(StageRemote, NewStage("first-remote", func(ctx *StageContext) error {
+		ctx.Server.Remote.URL = ctx.Detail.Remotes[0].URL
+		ctx.Server.Type = "remote"
+		ctx.Warn("always the first remote")
+		return nil
+	})); err != nil {
+		t.Fatalf("Replace failed: %v", err)
+	}
+	if err := pipeline.Disable(StageVariables); err != nil {
+		t.Fatalf("Disable failed: %v", err)
+	}
+
+	result, err := TransformJSONWithOptions(multiRemoteRegistryJSON, TransformOptions{Pipeline: pipeline})
+	if err != nil {
+		t.Fatalf("TransformJSONWithOptions failed: %v", err)
+	}
+
+	if result.Server.Name != "internal-com-example-multi-transport" {
+		t.Errorf("Expected inserted stage to rename the server, got %q", result.Server.Name)
+	}
+	if result.Server.Remote.URL != "https://mcp.example.com/sse" {
+		t.Errorf("Expected replaced remote stage, got %+v", result.Server.Remote)
+	}
+	if len(result.Warnings) != 1 || result.Warnings[0] != "always the first remote" {
+		t.Errorf("Unexpected warnings %v", result.Warnings)
+	}
+	stages := make(map[string]StageReport)
+	for _, report := range result.Stages {
+		stages[report.Stage] = report
+	}
+	if !stages[StageVariables].Disabled || stages["prefix"].Disabled {
+		t.Errorf("Unexpected stage reports %+v", result.Stages)
+	}
+	if _, ok := stages[StageRemote]; ok {
+		t.Errorf("Expected the remote stage to be replaced, got %+v", result.Stages)
+	}
+}
+
+func TestPipelineStageError(t *testing.T) {
+	pipeline := DefaultPipeline()
+	failure := errors.New("boom")
+	pipeline.Append(NewStage("fail", func(*StageContext) error { return failure }))
+
+	_, err := TransformJSONWithOptions(multiRemoteRegistryJSON, TransformOptions{Pipeline: pipeline})
+	if !errors.Is(err, failure) || !strings.Contains(err.Error(), "fail stage") {
+		t.Errorf("Expected wrapped stage error, got %v", err)
+	}
+}
+
+func TestPipelineUnknownStage(t *testing.T) {
+	pipeline := DefaultPipeline()
+	if err := pipeline.Disable("nope"); err == nil {
+		t.Error("Expected error disabling an unknown stage")
+	}
+	if err := pipeline.InsertBefore("nope", NewStage("x", func(*StageContext) error { return nil })); err == nil {
+		t.Error("Expected error inserting before an unknown stage")
+	}
+}
+
+func TestPipelineIdentity(t *testing.T) {
+	pipeline := DefaultPipeline()
+	if err := pipeline.Disable(StageIdentity); err == nil {
+		t.Error("Expected error disabling the identity stage")
+	}
+
+	// Later stages see the package even when identity is replaced
+	if err := pipeline.Replace(StageIdentity, NewStage("name", func(ctx *StageContext) error {
+		ctx.Server.Name = "notes"
+		return nil
+	})); err != nil {
+		t.Fatalf("Replace failed: %v", err)
+	}
+	registryJSON := `{
+		"server": {
+			"name": "io.github.example/notes",
+			"description": "Notes",
+			"version": "1.0.0",
+			"packages": [{"registryType": "oci", "identifier": "docker.io/example/notes", "version": "1.0.0", "transport": {"type": "stdio"}}]
+		}
+	}`
+	result, err := TransformJSONWithOptions(registryJSON, TransformOptions{Pipeline: pipeline})
+	if err != nil {
+		t.Fatalf("TransformJSONWithOptions failed: %v", err)
+	}
+	if result.Server.Name != "notes" || result.Server.Image != "docker.io/example/notes:1.0.0" {
+		t.Errorf("Expected the image from the package, got name %q image %q", result.Server.Name, result.Server.Image)
+	}
+}
+
+func TestPipelineOAuthWithoutMetadata(t *testing.T) {
+	registryJSON := `{
+		"server": {
+			"name": "io.github.example/notes",
+			"description": "Notes server",
+			"version": "1.0.0",
+			"remotes": [{"type": "streamable-http", "url": "https://mcp.example.com/mcp"}],
+			"_meta": {
+				"io.modelcontextprotocol.registry/publisher-provided": {
+					"oauth": {"providers": [{"provider": "example", "secret": "example.access_token", "env": "ACCESS_TOKEN"}]},
+					"icon": "https://example.com/notes.png",
+					"readme": "https://example.com/notes/README.md"
+				}
+			}
+		}
+	}`
+
+	pipeline := DefaultPipeline()
+	if err := pipeline.Disable(StageMetadata); err != nil {
+		t.Fatalf("Disable failed: %v", err)
+	}
+	result, err := TransformJSONWithOptions(registryJSON, TransformOptions{Pipeline: pipeline})
+	if err != nil {
+		t.Fatalf("TransformJSONWithOptions failed: %v", err)
+	}
+
+	server := result.Server
+	if server.OAuth == nil || len(server.OAuth.Providers) != 1 || server.OAuth.Providers[0].Provider != "example" {
+		t.Errorf("Expected OAuth without the metadata stage, got %+v", server.OAuth)
+	}
+	if server.Icon != "https://example.com/notes.png" {
+		t.Errorf("Expected the publisher icon without the metadata stage, got %q", server.Icon)
+	}
+	if server.ReadmeURL != "" {
+		t.Errorf("Expected the disabled metadata stage to skip the readme, got %q", server.ReadmeURL)
+	}
+}
@@ -0,0 +1,102 @@
+package catalogs
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+// PublisherMeta is the io.modelcontextprotocol.registry/publisher-provided
+// namespace as written by catalog-to-registry (add-metadata). The catalog
+// metadata map is merged into the namespace, so category, tags, license and
+// owner appear at the top level.
+type PublisherMeta struct {
+	Tools     []catalog.Tool   `json:"tools,omitempty"`
+	Prompts   int              `json:"prompts,omitempty"`
+	Resources map[string]any   `json:"resources,omitempty"`
+	Readme    string           `json:"readme,omitempty"`
+	ToolsURL  string           `json:"toolsUrl,omitempty"`
+	Upstream  string           `json:"upstream,omitempty"`
+	Source    string           `json:"source,omitempty"`
+	LongLived bool             `json:"longLived,omitempty"`
+	DateAdded string           `json:"dateAdded,omitempty"`
+	Icon      string           `json:"icon,omitempty"`
+	OAuth     *catalog.OAuth   `json:"oauth,omitempty"`
+	Metadata  catalog.Metadata `json:"metadata,omitempty"`
+}
+
+// decodeMetaValue decodes a loosely typed _meta value into target, leaving
+// target untouched when the value has the wrong type
+func decodeMetaValue(value any, target any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	decoded := reflect.New(reflect.TypeOf(target).Elem())
+	if err := json.Unmarshal(data, decoded.Interface()); err != nil {
+		return err
+	}
+	reflect.ValueOf(target).Elem().Set(decoded.Elem())
+	return nil
+}
+
+// decodePublisherMeta decodes the publisher-provided namespace, reporting
+// values whose type does not match the catalog field
+func decodePublisherMeta(raw map[string]interface{}) (*PublisherMeta, []string) {
+	meta := &PublisherMeta{}
+	fields := []struct {
+		key    string
+		target any
+	}{
+		{"tools", &meta.Tools},
+		{"prompts", &meta.Prompts},
+		{"resources", &meta.Resources},
+		{"readme", &meta.Readme},
+		{"toolsUrl", &meta.ToolsURL},
+		{"upstream", &meta.Upstream},
+		{"source", &meta.Source},
+		{"longLived", &meta.LongLived},
+		{"dateAdded", &meta.DateAdded},
+		{"icon", &meta.Icon},
+		{"oauth", &meta.OAuth},
+		// A nested metadata map first, then the merged top-level keys
+		{"metadata", &meta.Metadata},
+		{"category", &meta.Metadata.Category},
+		{"tags", &meta.Metadata.Tags},
+		{"license", &meta.Metadata.License},
+		{"owner", &meta.Metadata.Owner},
+	}
+
+	var warnings []string
+	for _, field := range fields {
+		value, ok := raw[field.key]
+		if !ok || value == nil {
+			continue
+		}
+		if err := decodeMetaValue(value, field.target); err != nil {
+			warnings = append(warnings, fmt.Sprintf("publisher-provided _meta %s has the wrong type (%T): %v", field.key, value, err))
+		}
+	}
+	return meta, warnings
+}
+
+// applyPublisherMeta copies the publisher-provided fields that have a
+// catalog.Server equivalent, except the icon and OAuth, which have their own
+// pipeline stages
+func applyPublisherMeta(meta *PublisherMeta, server *catalog.Server) {
+	if len(meta.Tools) > 0 {
+		server.Tools = meta.Tools
+	}
+	if meta.Readme != "" {
+		server.ReadmeURL = meta.Readme
+	}
+	if meta.LongLived {
+		server.LongLived = true
+	}
+	if !reflect.DeepEqual(meta.Metadata, catalog.Metadata{}) {
+		metadata := meta.Metadata
+		server.Metadata = &metadata
+	}
+}
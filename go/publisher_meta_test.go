@@ -0,0 +1,104 @@
+package catalogs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTransformPublisherMeta(t *testing.T) {
+	registryJSON := `{
+		"server": {
+			"name": "io.github.example/notes",
+			"description": "Notes server",
+			"version": "1.0.0",
+			"packages": [
+				{
+					"registryType": "oci",
+					"identifier": "docker.io/example/notes",
+					"version": "1.0.0",
+					"transport": {"type": "stdio"}
+				}
+			],
+			"_meta": {
+				"io.modelcontextprotocol.registry/publisher-provided": {
+					"tools": [{"name": "add_note", "description": "Add a note"}, {"name": "list_notes", "description": "List notes"}],
+					"prompts": 2,
+					"resources": {},
+					"readme": "https://example.com/notes/README.md",
+					"toolsUrl": "https://example.com/notes/tools.json",
+					"upstream": "https://github.com/example/notes",
+					"source": "https://github.com/example/notes/tree/main",
+					"longLived": true,
+					"dateAdded": "2025-05-06T00:00:00Z",
+					"icon": "https://example.com/notes.png",
+					"category": "productivity",
+					"tags": ["notes", "productivity"],
+					"license": "MIT License",
+					"owner": "example"
+				}
+			}
+		}
+	}`
+
+	result, err := TransformJSONWithOptions(registryJSON, TransformOptions{})
+	if err != nil {
+		t.Fatalf("TransformJSONWithOptions failed: %v", err)
+	}
+	if len(result.Warnings) > 0 {
+		t.Errorf("Expected no warnings, got %v", result.Warnings)
+	}
+
+	server := result.Server
+	if len(server.Tools) != 2 || server.Tools[0].Name != "add_note" {
+		t.Errorf("Unexpected tools %+v", server.Tools)
+	}
+	if server.ReadmeURL != "https://example.com/notes/README.md" {
+		t.Errorf("Unexpected readme %s", server.ReadmeURL)
+	}
+	if !server.LongLived {
+		t.Error("Expected longLived")
+	}
+	if server.Icon != "https://example.com/notes.png" {
+		t.Errorf("Unexpected icon %s", server.Icon)
+	}
+	if server.Metadata == nil || server.Metadata.Category != "productivity" || len(server.Metadata.Tags) != 2 || server.Metadata.License != "MIT License" || server.Metadata.Owner != "example" {
+		t.Errorf("Unexpected metadata %+v", server.Metadata)
+	}
+
+	publisher := result.Publisher
+	if publisher.Prompts != 2 || publisher.ToolsURL != "https://example.com/notes/tools.json" || publisher.Upstream != "https://github.com/example/notes" || publisher.DateAdded != "2025-05-06T00:00:00Z" {
+		t.Errorf("Unexpected publisher meta %+v", publisher)
+	}
+}
+
+func TestDecodePublisherMetaTypeMismatch(t *testing.T) {
+	meta, warnings := decodePublisherMeta(map[string]interface{}{
+		"tools":     "add_note",
+		"prompts":   "two",
+		"longLived": "yes",
+		"readme":    "https://example.com/README.md",
+		"metadata":  map[string]interface{}{"category": "data", "tags": []interface{}{"a"}},
+	})
+
+	if len(warnings) != 3 {
+		t.Fatalf("Expected three type mismatch warnings, got %v", warnings)
+	}
+	for _, key := range []string{"tools", "prompts", "longLived"} {
+		found := false
+		for _, warning := range warnings {
+			if strings.Contains(warning, "_meta "+key+" has the wrong type") {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Expected a warning for %s in %v", key, warnings)
+		}
+	}
+
+	if meta.Tools != nil || meta.Prompts != 0 || meta.LongLived {
+		t.Errorf("Expected mismatched fields to stay empty, got %+v", meta)
+	}
+	if meta.Readme != "https://example.com/README.md" || meta.Metadata.Category != "data" {
+		t.Errorf("Expected well typed fields to decode, got %+v", meta)
+	}
+}
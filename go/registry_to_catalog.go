@@ -3,6 +3,7 @@ package catalogs
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/docker/mcp-gateway/pkg/catalog"
@@ -60,6 +61,10 @@ func collectVariables(serverDetail ServerDetail) map[string]model.Input {
 
 	// Collect from remotes
 	for _, remote := range serverDetail.Remotes {
+		// From URL template variables
+		for k, v := range remote.Variables {
+			variables[k] = v
+		}
 		for _, header := range remote.Headers {
 			for k, v := range header.Variables {
 				variables[k] = v
@@ -111,6 +116,8 @@ func buildConfigSchema(configVars map[string]model.Input, serverName string) []a
 			required = append(required, varName)
 		}
 	}
+	// Map order would make regenerated entries differ from run to run
+	sort.Strings(required)
 
 	return []any{
 		map[string]any{
@@ -138,9 +145,14 @@ func buildSecrets(serverName string, secretVars map[string]model.Input) []catalo
 	return secrets
 }
 
+// extractImageInfo renders the image reference of an OCI package: a digest
+// version after @, a tag after :
 func extractImageInfo(pkg model.Package) string {
-	if pkg.RegistryType == "oci" && pkg.Transport.Type == "stdio" {
-		return fmt.Sprintf("%s@%s", pkg.Identifier, pkg.Version)
+	if pkg.RegistryType == "oci" && (pkg.Transport.Type == "stdio" || isHTTPTransport(pkg.Transport)) {
+		if strings.HasPrefix(pkg.Version, "sha256:") {
+			return fmt.Sprintf("%s@%s", pkg.Identifier, pkg.Version)
+		}
+		return fmt.Sprintf("%s:%s", pkg.Identifier, pkg.Version)
 	}
 	return ""
 }
@@ -211,75 +223,6 @@ func parseRuntimeArg(arg model.Argument) string {
 	return value
 }
 
-func extractUserFromRuntimeArgs(runtimeArgs []model.Argument) string {
-	for _, arg := range runtimeArgs {
-		if arg.Type == model.ArgumentTypeNamed && arg.Name == "-u" {
-			value := arg.Value
-			if len(arg.Variables) > 0 {
-				value = restoreInterpolatedValue(value, arg.Variables)
-			}
-			// Extract value after '='
-			parts := strings.SplitN(value, "=", 2)
-			if len(parts) == 2 {
-				return parts[1]
-			}
-			return value
-		}
-	}
-	return ""
-}
-
-func extractVolumesFromRuntimeArgs(runtimeArgs []model.Argument) []string {
-	var volumes []string
-
-	for _, arg := range runtimeArgs {
-		if arg.Type != model.ArgumentTypeNamed {
-			continue
-		}
-
-		value := arg.Value
-		if len(arg.Variables) > 0 {
-			value = restoreInterpolatedValue(value, arg.Variables)
-		}
-
-		switch arg.Name {
-		case "--mount":
-			// For --mount, parse and convert to simple src:dst format
-			// Input: "type=bind,src={{source_path}},dst={{target_path}}"
-			// Output: "{{source_path}}:{{target_path}}"
-			var src, dst string
-			parts := strings.Split(value, ",")
-			for _, part := range parts {
-				kv := strings.SplitN(part, "=", 2)
-				if len(kv) == 2 {
-					switch kv[0] {
-					case "src", "source":
-						src = kv[1]
-					case "dst", "destination", "target":
-						dst = kv[1]
-					}
-				}
-			}
-			if src != "" && dst != "" {
-				volumes = append(volumes, fmt.Sprintf("%s:%s", src, dst))
-			} else {
-				// Fallback to full value if parsing fails
-				volumes = append(volumes, value)
-			}
-		case "-v":
-			// For -v, extract value after '=' if present
-			parts := strings.SplitN(value, "=", 2)
-			if len(parts) == 2 {
-				volumes = append(volumes, parts[1])
-			} else {
-				volumes = append(volumes, value)
-			}
-		}
-	}
-
-	return volumes
-}
-
 func convertPackageArgsToCommand(packageArgs []model.Argument) []string {
 	if len(packageArgs) == 0 {
 		return nil
@@ -293,12 +236,20 @@ func convertPackageArgsToCommand(packageArgs []model.Argument) []string {
 	return command
 }
 
-func convertRemote(remote model.Transport) catalog.Remote {
+func convertRemote(remote model.Transport) (catalog.Remote, error) {
 	catalogRemote := catalog.Remote{
 		URL:       remote.URL,
 		Transport: remote.Type,
 	}
 
+	if len(remote.Variables) > 0 {
+		// Templated URLs can't be normalized; validate a sample rendering instead
+		if err := validateURLTemplate(remote.URL, remote.Variables); err != nil {
+			return catalog.Remote{}, err
+		}
+		catalogRemote.URL = restoreInterpolatedValue(remote.URL, remote.Variables)
+	}
+
 	if len(remote.Headers) > 0 {
 		headers := make(map[string]string)
 		for _, header := range remote.Headers {
@@ -311,7 +262,7 @@ func convertRemote(remote model.Transport) catalog.Remote {
 		catalogRemote.Headers = headers
 	}
 
-	return catalogRemote
+	return catalogRemote, nil
 }
 
 func getPublisherProvidedMeta(meta *v0.ServerMeta) map[string]interface{} {
@@ -323,114 +274,219 @@ func getPublisherProvidedMeta(meta *v0.ServerMeta) map[string]interface{} {
 
 // TransformToDocker transforms a ServerDetail (community format) to catalog.Server (catalog format)
 func TransformToDocker(serverDetail ServerDetail) (*catalog.Server, error) {
-	serverName := extractServerName(serverDetail.Name)
-
-	var pkg *model.Package
-	if len(serverDetail.Packages) > 0 {
-		pkg = &serverDetail.Packages[0]
+	result, err := transform(serverDetail, DefaultTransportPreference)
+	if err != nil {
+		return nil, err
 	}
+	return result.Server, nil
+}
 
-	var remote *model.Transport
-	if len(serverDetail.Remotes) > 0 {
-		remote = &serverDetail.Remotes[0]
-	}
+// transform is TransformToDocker with a transport preference, also returning
+// the alternate remotes and warnings about anything that could not be converted
+func transform(serverDetail ServerDetail, transports []string) (*TransformResult, error) {
+	return DefaultPipeline().Run(serverDetail, transports)
+}
+
+// TransformOptions control TransformJSONWithOptions
+type TransformOptions struct {
+	// SecretScan checks the registry input and the catalog output for
+	// hardcoded secrets. The zero value disables scanning.
+	SecretScan SecretScanMode
+	// NetworkPolicy derives DisableNetwork and AllowHosts for image servers
+	NetworkPolicy *NetworkPolicy
+	// RemoteURLs decides whether unsafe remote URLs, including alternate
+	// remotes, are warnings or errors. Setting it also normalizes the URLs.
+	// The zero value keeps the URLs as published and only warns.
+	RemoteURLs RemoteURLMode
+	// Admission rejects servers that fail the admission policy
+	Admission *AdmissionPolicy
+	// Transports orders remote transports by preference. When nil,
+	// DefaultTransportPreference is used.
+	Transports []string
+	// Status decides whether deprecated and deleted servers are skipped or
+	// only reported. The zero value warns.
+	Status StatusMode
+	// Overlays are server-specific fixes keyed by catalog server name,
+	// applied right after the transform
+	Overlays map[string]*Overlay
+	// Pipeline replaces the default transform stages
+	Pipeline *Pipeline
+	// Rewrites are JSONPath rules applied to the registry input before the
+	// transform and to the catalog output before overlays
+	Rewrites *RewriteRules
+}
+
+// TransformResult is the outcome of TransformJSONWithOptions
+type TransformResult struct {
+	Server   *catalog.Server
+	JSON     string
+	Warnings []string
+	// Egress is the network policy decision, when a policy was given
+	Egress *EgressDecision
+	// Admission holds the per-rule results, when a policy was given
+	Admission *AdmissionResult
+	// AlternateRemotes are the published remotes that were not selected,
+	// kept in the catalog metadata so a catalog can switch when a transport
+	// is deprecated
+	AlternateRemotes []catalog.Remote
+	// ContainerTransport is the port and path to connect to when the image
+	// serves streamable-http or sse instead of stdio
+	ContainerTransport *ContainerTransport
+	// Publisher is the decoded publisher-provided _meta, including fields
+	// with no catalog.Server equivalent (prompts, resources, toolsUrl, ...)
+	Publisher *PublisherMeta
+	// Official is the registry's status, publish dates and isLatest flag,
+	// also written to the catalog metadata
+	Official *v0.RegistryExtensions
+	// Overlay is the overlay that was applied, if any
+	Overlay *Overlay
+	// Rewrites are the rewrite rule changes that were made
+	Rewrites []RuleApplication
+	// Stages are the timing and warnings of each pipeline stage
+	Stages []StageReport
+}
 
-	variables := collectVariables(serverDetail)
-	secretVars, configVars := separateSecretsAndConfig(variables)
+// TransformJSON transforms community registry JSON to catalog JSON
+func TransformJSON(registryJSON string) (string, error) {
+	result, err := TransformJSONWithOptions(registryJSON, TransformOptions{})
+	if err != nil {
+		return "", err
+	}
+	return result.JSON, nil
+}
 
-	server := &catalog.Server{
-		Name:        serverName,
-		Title:       serverDetail.Title,
-		Description: serverDetail.Description,
+// TransformJSONWithOptions transforms community registry JSON to catalog JSON,
+// reporting anything suspicious as warnings or, depending on options, errors
+func TransformJSONWithOptions(registryJSON string, opts TransformOptions) (*TransformResult, error) {
+	serverResponse, err := parseServerResponse([]byte(registryJSON))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse registry JSON: %w", err)
 	}
+	return transformResponse(serverResponse, opts)
+}
 
-	// Add image if it's an OCI package
-	if pkg != nil {
-		if image := extractImageInfo(*pkg); image != "" {
-			server.Image = image
-			server.Type = "server"
+// transformResponse applies the transform options to one registry entry
+func transformResponse(serverResponse v0.ServerResponse, opts TransformOptions) (*TransformResult, error) {
+	var rewrites []RuleApplication
+	if opts.Rewrites != nil {
+		var err error
+		if serverResponse, rewrites, err = opts.Rewrites.rewriteInput(serverResponse); err != nil {
+			return nil, fmt.Errorf("failed to apply input rules: %w", err)
 		}
 	}
 
-	// Add remote if present
-	if remote != nil {
-		remoteVal := convertRemote(*remote)
-		server.Remote = remoteVal
-		server.Type = "remote"
+	statusWarnings, err := checkStatus(opts.Status, serverResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline := opts.Pipeline
+	if pipeline == nil {
+		pipeline = DefaultPipeline()
+	}
+	result, err := pipeline.Run(serverResponse.Server, opts.Transports)
+	if err != nil {
+		return nil, fmt.Errorf("failed to transform: %w", err)
 	}
+	dockerServer := result.Server
+	result.Official = serverResponse.Meta.Official
+	result.Warnings = append(statusWarnings, result.Warnings...)
 
-	// Add config schema if we have config variables
-	if len(configVars) > 0 {
-		server.Config = buildConfigSchema(configVars, serverName)
+	if opts.Rewrites != nil {
+		outputRewrites, err := opts.Rewrites.rewriteOutput(dockerServer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply output rules: %w", err)
+		}
+		result.Rewrites = append(rewrites, outputRewrites...)
 	}
 
-	// Add secrets if we have secret variables
-	if len(secretVars) > 0 {
-		server.Secrets = buildSecrets(serverName, secretVars)
+	if opts.NetworkPolicy != nil {
+		decision := opts.NetworkPolicy.DeriveEgress(serverResponse.Server, dockerServer)
+		decision.Apply(dockerServer)
+		result.Egress = &decision
 	}
 
-	// Add environment variables
-	if pkg != nil && len(pkg.EnvironmentVariables) > 0 {
-		server.Env = convertEnvVariables(pkg.EnvironmentVariables, configVars, serverName)
+	if opts.RemoteURLs != "" {
+		normalizeRemoteURLs(dockerServer, result.AlternateRemotes)
 	}
 
-	// Add command from package arguments
-	if pkg != nil && len(pkg.PackageArguments) > 0 {
-		server.Command = convertPackageArgsToCommand(pkg.PackageArguments)
+	// Overlays are manual fixes and have the last word; the checks below
+	// still see what they set
+	if overlay, ok := opts.Overlays[dockerServer.Name]; ok {
+		if err := overlay.Apply(dockerServer); err != nil {
+			return nil, fmt.Errorf("failed to apply overlay %s: %w", overlay.Source, err)
+		}
+		result.Overlay = overlay
 	}
 
-	// Add user from runtime arguments
-	if pkg != nil {
-		if user := extractUserFromRuntimeArgs(pkg.RuntimeArguments); user != "" {
-			server.User = user
+	var remoteProblems []RemoteURLProblem
+	if dockerServer.Remote.URL != "" {
+		remoteProblems = append(remoteProblems, CheckRemoteURL(dockerServer.Remote.URL)...)
+	}
+	for _, alternate := range result.AlternateRemotes {
+		remoteProblems = append(remoteProblems, CheckRemoteURL(alternate.URL)...)
+	}
+	// The catalog URLs only show the placeholders; check what the
+	// publisher's defaults and choices render to
+	for _, remote := range serverResponse.Server.Remotes {
+		if validateURLTemplate(remote.URL, remote.Variables) == nil {
+			remoteProblems = append(remoteProblems, checkURLTemplate(remote.URL, remote.Variables)...)
 		}
 	}
+	remoteWarnings, blocking := applyRemoteURLMode(opts.RemoteURLs, remoteProblems)
+	if len(blocking) > 0 {
+		return nil, &UnsafeRemoteURLError{Problems: blocking}
+	}
+	result.Warnings = append(result.Warnings, remoteWarnings...)
 
-	// Add volumes from runtime arguments
-	if pkg != nil {
-		if volumes := extractVolumesFromRuntimeArgs(pkg.RuntimeArguments); len(volumes) > 0 {
-			server.Volumes = volumes
+	if opts.SecretScan != "" && opts.SecretScan != SecretScanOff {
+		findings, err := scanTransformSecrets(serverResponse.Server, dockerServer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan for secrets: %w", err)
+		}
+		if len(findings) > 0 && opts.SecretScan == SecretScanDeny {
+			return nil, &SecretsFoundError{Findings: findings}
+		}
+		for _, finding := range findings {
+			result.Warnings = append(result.Warnings, finding.String())
 		}
 	}
 
-	// Add metadata from publisher-provided
-	if publisherMeta := getPublisherProvidedMeta(serverDetail.Meta); publisherMeta != nil {
-		if oauthData, ok := publisherMeta["oauth"]; ok {
-			// Try to convert to catalog.OAuth
-			if oauthJSON, err := json.Marshal(oauthData); err == nil {
-				var oauth catalog.OAuth
-				if err := json.Unmarshal(oauthJSON, &oauth); err == nil {
-					server.OAuth = &oauth
-				}
-			}
+	if opts.Admission != nil {
+		admission := opts.Admission.EvaluateInput(*dockerServer, serverResponse.Server)
+		if !admission.Admitted() {
+			return nil, &AdmissionError{Result: admission}
 		}
+		result.Admission = &admission
 	}
 
-	// Add icon
-	if len(serverDetail.Icons) > 0 {
-		server.Icon = serverDetail.Icons[0].Src
+	catalogJSON, err := json.MarshalIndent(newCatalogServerJSON(result), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal catalog JSON: %w", err)
 	}
+	result.JSON = string(catalogJSON)
 
-	return server, nil
+	return result, nil
 }
 
-// TransformJSON transforms community registry JSON to catalog JSON
-func TransformJSON(registryJSON string) (string, error) {
-	var serverResponse v0.ServerResponse
-
-	if err := json.Unmarshal([]byte(registryJSON), &serverResponse); err != nil {
-		return "", fmt.Errorf("failed to parse registry JSON: %w", err)
-	}
-
-	dockerServer, err := TransformToDocker(serverResponse.Server)
+func scanTransformSecrets(serverDetail ServerDetail, server *catalog.Server) ([]SecretFinding, error) {
+	inputFindings, err := ScanServerDetailSecrets(serverDetail)
 	if err != nil {
-		return "", fmt.Errorf("failed to transform: %w", err)
+		return nil, err
 	}
-
-	catalogJSON, err := json.MarshalIndent(dockerServer, "", "  ")
+	outputFindings, err := ScanCatalogSecrets(server)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal catalog JSON: %w", err)
+		return nil, err
 	}
 
-	return string(catalogJSON), nil
+	var findings []SecretFinding
+	for _, finding := range inputFindings {
+		finding.Path = "input." + finding.Path
+		findings = append(findings, finding)
+	}
+	for _, finding := range outputFindings {
+		finding.Path = "output." + finding.Path
+		findings = append(findings, finding)
+	}
+	return findings, nil
 }
@@ -2,9 +2,11 @@ package catalogs
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/modelcontextprotocol/registry/pkg/model"
 )
 
 func TestTransformOCIPackage(t *testing.T) {
@@ -205,6 +207,50 @@ func TestTransformOCIPackage(t *testing.T) {
 	t.Logf("Catalog JSON:\n%s", catalogJSON)
 }
 
+func TestTransformOCIPackageImageReference(t *testing.T) {
+	// Digests stay pinned after @; any other version is a tag after :.
+	// Catalogs written before tags were told apart used identifier@version
+	// for both.
+	tests := []struct {
+		version  string
+		expected string
+	}{
+		{"sha256:abc123def456", "docker.io/mcp/filesystem@sha256:abc123def456"},
+		{"1.0.2", "docker.io/mcp/filesystem:1.0.2"},
+		{"latest", "docker.io/mcp/filesystem:latest"},
+	}
+
+	for _, tt := range tests {
+		registryJSON := `{
+			"server": {
+				"name": "io.github.modelcontextprotocol/filesystem",
+				"description": "Node.js server implementing Model Context Protocol (MCP) for filesystem operations",
+				"version": "1.0.2",
+				"packages": [
+					{
+						"registryType": "oci",
+						"identifier": "docker.io/mcp/filesystem",
+						"version": "` + tt.version + `",
+						"transport": {"type": "stdio"}
+					}
+				]
+			}
+		}`
+
+		catalogJSON, err := TransformJSON(registryJSON)
+		if err != nil {
+			t.Fatalf("TransformJSON failed for %s: %v", tt.version, err)
+		}
+		var result catalog.Server
+		if err := json.Unmarshal([]byte(catalogJSON), &result); err != nil {
+			t.Fatalf("Failed to unmarshal result: %v", err)
+		}
+		if result.Image != tt.expected {
+			t.Errorf("Expected image '%s' for version %s, got '%s'", tt.expected, tt.version, result.Image)
+		}
+	}
+}
+
 func TestTransformRemote(t *testing.T) {
 	// Example with remote server (Google Maps Grounding Lite)
 	registryJSON := `{
@@ -631,3 +677,18 @@ func TestTransformOCIWithDirectSecrets(t *testing.T) {
 
 	t.Logf("Catalog JSON:\n%s", catalogJSON)
 }
+
+func TestBuildConfigSchemaRequiredSorted(t *testing.T) {
+	configVars := map[string]model.Input{
+		"region":  {IsRequired: true},
+		"account": {IsRequired: true},
+		"project": {IsRequired: true},
+		"debug":   {},
+	}
+	for i := 0; i < 10; i++ {
+		schema := buildConfigSchema(configVars, "com-example-weather")[0].(map[string]any)
+		if required := schema["required"].([]string); strings.Join(required, ",") != "account,project,region" {
+			t.Fatalf("Expected required in name order, got %v", required)
+		}
+	}
+}
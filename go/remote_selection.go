@@ -0,0 +1,86 @@
+package catalogs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// DefaultTransportPreference prefers streamable-http over the deprecated sse
+// transport when a server publishes both
+var DefaultTransportPreference = []string{model.TransportTypeStreamableHTTP, model.TransportTypeSSE}
+
+// ParseTransportPreference parses a comma separated -transports flag value
+func ParseTransportPreference(s string) ([]string, error) {
+	var preference []string
+	for _, transport := range strings.Split(s, ",") {
+		switch transport = strings.TrimSpace(transport); transport {
+		case "":
+			continue
+		case model.TransportTypeStreamableHTTP, model.TransportTypeSSE:
+			preference = append(preference, transport)
+		default:
+			return nil, fmt.Errorf("invalid transport %q (expected streamable-http or sse)", transport)
+		}
+	}
+	if len(preference) == 0 {
+		return nil, fmt.Errorf("no transports given")
+	}
+	return preference, nil
+}
+
+// selectRemote picks the remote with the most preferred transport, returning
+// its index and the indexes of the alternates in declaration order. When no
+// remote uses a preferred transport, the first remote is used as a fallback.
+func selectRemote(remotes []model.Transport, preference []string) (selected int, alternates []int, warnings []string) {
+	if len(remotes) == 0 {
+		return -1, nil, nil
+	}
+	if len(preference) == 0 {
+		preference = DefaultTransportPreference
+	}
+
+	selected = -1
+	for _, transport := range preference {
+		for i, remote := range remotes {
+			if remote.Type == transport {
+				selected = i
+				break
+			}
+		}
+		if selected >= 0 {
+			break
+		}
+	}
+	if selected < 0 {
+		selected = 0
+		warnings = append(warnings, fmt.Sprintf("no remote uses a preferred transport (%s), falling back to %s remote %s",
+			strings.Join(preference, ", "), remotes[0].Type, redactURL(remotes[0].URL)))
+	}
+
+	for i := range remotes {
+		if i != selected {
+			alternates = append(alternates, i)
+		}
+	}
+	return selected, alternates, warnings
+}
+
+// convertAlternateRemotes converts the remotes that were not selected so they
+// can be kept alongside the catalog entry. Remotes that fail to convert are
+// reported as warnings.
+func convertAlternateRemotes(remotes []model.Transport, alternates []int) ([]catalog.Remote, []string) {
+	var converted []catalog.Remote
+	var warnings []string
+	for _, i := range alternates {
+		remote, err := convertRemote(remotes[i])
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("alternate %s remote dropped: %v", remotes[i].Type, err))
+			continue
+		}
+		converted = append(converted, remote)
+	}
+	return converted, warnings
+}
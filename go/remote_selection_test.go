@@ -0,0 +1,87 @@
+package catalogs
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+const multiRemoteRegistryJSON = `{
+	"server": {
+		"name": "com.example/multi-transport",
+		"description": "Server with sse and streamable-http remotes",
+		"version": "1.0.0",
+		"remotes": [
+			{"type": "sse", "url": "https://mcp.example.com/sse"},
+			{"type": "streamable-http", "url": "https://mcp.example.com/mcp"}
+		]
+	}
+}`
+
+func TestTransformPrefersStreamableHTTP(t *testing.T) {
+	result, err := TransformJSONWithOptions(multiRemoteRegistryJSON, TransformOptions{})
+	if err != nil {
+		t.Fatalf("TransformJSONWithOptions failed: %v", err)
+	}
+
+	if result.Server.Remote.URL != "https://mcp.example.com/mcp" || result.Server.Remote.Transport != "streamable-http" {
+		t.Errorf("Expected streamable-http remote, got %+v", result.Server.Remote)
+	}
+	if len(result.AlternateRemotes) != 1 || result.AlternateRemotes[0].Transport != "sse" {
+		t.Errorf("Expected sse remote kept as alternate, got %+v", result.AlternateRemotes)
+	}
+
+	// The catalog entry keeps the alternates in its metadata
+	var entry struct {
+		Metadata EntryMetadata `json:"metadata"`
+	}
+	if err := json.Unmarshal([]byte(result.JSON), &entry); err != nil {
+		t.Fatalf("Failed to parse catalog JSON: %v", err)
+	}
+	alternates := entry.Metadata.AlternateRemotes
+	if len(alternates) != 1 || alternates[0].URL != "https://mcp.example.com/sse" || alternates[0].Transport != "sse" {
+		t.Errorf("Expected the sse remote in the catalog metadata, got %+v", alternates)
+	}
+}
+
+func TestTransformTransportPreference(t *testing.T) {
+	result, err := TransformJSONWithOptions(multiRemoteRegistryJSON, TransformOptions{Transports: []string{"sse", "streamable-http"}})
+	if err != nil {
+		t.Fatalf("TransformJSONWithOptions failed: %v", err)
+	}
+
+	if result.Server.Remote.Transport != "sse" {
+		t.Errorf("Expected sse remote, got %+v", result.Server.Remote)
+	}
+}
+
+func TestSelectRemoteFallback(t *testing.T) {
+	remotes := []model.Transport{{Type: "sse", URL: "https://mcp.example.com/sse"}}
+
+	selected, alternates, warnings := selectRemote(remotes, []string{"streamable-http"})
+	if selected != 0 || len(alternates) != 0 {
+		t.Errorf("Expected fallback to the only remote, got %d %v", selected, alternates)
+	}
+	if len(warnings) != 1 {
+		t.Errorf("Expected a fallback warning, got %v", warnings)
+	}
+
+	if selected, _, _ := selectRemote(nil, nil); selected != -1 {
+		t.Errorf("Expected no selection without remotes, got %d", selected)
+	}
+}
+
+func TestParseTransportPreference(t *testing.T) {
+	preference, err := ParseTransportPreference("sse, streamable-http")
+	if err != nil {
+		t.Fatalf("ParseTransportPreference failed: %v", err)
+	}
+	if len(preference) != 2 || preference[0] != "sse" {
+		t.Errorf("Unexpected preference %v", preference)
+	}
+
+	if _, err := ParseTransportPreference("stdio"); err == nil {
+		t.Error("Expected stdio to be rejected")
+	}
+}
@@ -0,0 +1,183 @@
+package catalogs
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+// RemoteURLMode decides what happens to unsafe remote URLs
+type RemoteURLMode string
+
+const (
+	// RemoteURLWarn reports unsafe remote URLs as warnings. The zero value
+	// warns too, but leaves the URLs as published.
+	RemoteURLWarn RemoteURLMode = "warn"
+	// RemoteURLDeny rejects unsafe remote URLs. It is the CLI default.
+	RemoteURLDeny RemoteURLMode = "deny"
+	// RemoteURLAllowInternal accepts http, loopback and private addresses for
+	// internal catalogs. Embedded credentials are still rejected.
+	RemoteURLAllowInternal RemoteURLMode = "allow-internal"
+)
+
+// ParseRemoteURLMode parses a -remote-url-policy flag value
+func ParseRemoteURLMode(s string) (RemoteURLMode, error) {
+	switch mode := RemoteURLMode(s); mode {
+	case RemoteURLWarn, RemoteURLDeny, RemoteURLAllowInternal:
+		return mode, nil
+	}
+	return "", fmt.Errorf("invalid remote URL policy %q (expected warn, deny or allow-internal)", s)
+}
+
+// RemoteURLProblem is one reason a remote URL is unsafe
+type RemoteURLProblem struct {
+	URL    string `json:"url"`
+	Reason string `json:"reason"`
+	// Internal problems are acceptable for internal catalogs
+	Internal bool `json:"internal,omitempty"`
+}
+
+func (p RemoteURLProblem) String() string {
+	return fmt.Sprintf("unsafe remote URL %s: %s", redactURL(p.URL), p.Reason)
+}
+
+// UnsafeRemoteURLError is returned when RemoteURLDeny finds problems
+type UnsafeRemoteURLError struct {
+	Problems []RemoteURLProblem
+}
+
+func (e *UnsafeRemoteURLError) Error() string {
+	lines := make([]string, 0, len(e.Problems))
+	for _, problem := range e.Problems {
+		lines = append(lines, problem.String())
+	}
+	return strings.Join(lines, "; ")
+}
+
+// redactURL hides embedded credentials so they don't end up in logs
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.User == nil {
+		return raw
+	}
+	u.User = url.User("****")
+	return u.String()
+}
+
+// NormalizeRemoteURL lowercases the scheme and host, drops the default port,
+// the fragment and any trailing slash
+func NormalizeRemoteURL(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("remote URL %q needs a scheme and host", raw)
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	host, port := strings.ToLower(u.Hostname()), u.Port()
+	if (u.Scheme == "https" && port == "443") || (u.Scheme == "http" && port == "80") {
+		port = ""
+	}
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	if port != "" {
+		host = host + ":" + port
+	}
+	u.Host = host
+	u.Fragment = ""
+	u.RawFragment = ""
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.RawPath = strings.TrimRight(u.RawPath, "/")
+
+	return u.String(), nil
+}
+
+// normalizeRemoteURLs normalizes the selected and alternate remote URLs.
+// Templated URLs and URLs that don't parse are kept for the checks to report.
+func normalizeRemoteURLs(server *catalog.Server, alternates []catalog.Remote) {
+	normalize := func(remote *catalog.Remote) {
+		if remote.URL == "" || placeholderPattern.MatchString(remote.URL) {
+			return
+		}
+		if normalized, err := NormalizeRemoteURL(remote.URL); err == nil {
+			remote.URL = normalized
+		}
+	}
+	normalize(&server.Remote)
+	for i := range alternates {
+		normalize(&alternates[i])
+	}
+}
+
+var internalHostSuffixes = []string{".localhost", ".local", ".internal"}
+
+// CheckRemoteURL returns the reasons a remote URL is unsafe to put in a catalog.
+// Interpolated parts of the URL are checked as if they held a sample value.
+func CheckRemoteURL(raw string) []RemoteURLProblem {
+	problem := func(reason string, internal bool) RemoteURLProblem {
+		return RemoteURLProblem{URL: raw, Reason: reason, Internal: internal}
+	}
+
+	u, err := url.Parse(withSamplePlaceholders(raw))
+	if err != nil {
+		return []RemoteURLProblem{problem(fmt.Sprintf("invalid URL: %v", err), false)}
+	}
+
+	var problems []RemoteURLProblem
+	switch u.Scheme {
+	case "https":
+	case "http":
+		problems = append(problems, problem("uses http instead of https", true))
+	default:
+		problems = append(problems, problem(fmt.Sprintf("unsupported scheme %q", u.Scheme), false))
+	}
+	if u.User != nil {
+		problems = append(problems, problem("embeds credentials", false))
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if ip := net.ParseIP(host); ip != nil {
+		switch {
+		case ip.IsLoopback():
+			problems = append(problems, problem("targets a loopback address", true))
+		case ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast():
+			problems = append(problems, problem("targets a link-local address", true))
+		case ip.IsPrivate():
+			problems = append(problems, problem("targets a private (RFC 1918) address", true))
+		case ip.IsUnspecified() || ip.IsMulticast():
+			problems = append(problems, problem("targets a non-routable address", false))
+		}
+	} else if host == "localhost" {
+		problems = append(problems, problem("targets localhost", true))
+	} else {
+		for _, suffix := range internalHostSuffixes {
+			if strings.HasSuffix(host, suffix) {
+				problems = append(problems, problem(fmt.Sprintf("targets an internal %s host", suffix), true))
+				break
+			}
+		}
+	}
+
+	return problems
+}
+
+// applyRemoteURLMode splits problems into warnings and blocking problems
+func applyRemoteURLMode(mode RemoteURLMode, problems []RemoteURLProblem) (warnings []string, blocking []RemoteURLProblem) {
+	for _, problem := range problems {
+		switch {
+		case mode == RemoteURLAllowInternal && problem.Internal:
+			continue
+		case mode == RemoteURLWarn || mode == "":
+			warnings = append(warnings, problem.String())
+		default:
+			blocking = append(blocking, problem)
+		}
+	}
+	return warnings, blocking
+}
@@ -0,0 +1,286 @@
+package catalogs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/eval"
+)
+
+// RenderOptions are what the gateway combines with a catalog server to start
+// its container. The defaults match docker mcp gateway run.
+type RenderOptions struct {
+	// Config holds config values keyed by server name, as in the gateway's
+	// config.yaml: {server-name: {key: value}}
+	Config map[string]any
+	// Secrets maps secret names to references (e.g. a secret store key).
+	// References stand in for values; nothing is read.
+	Secrets map[string]string
+	// Networks are attached when the server's network is not disabled
+	Networks []string
+	// ReadOnly mounts volumes read-only, except for long-lived servers
+	ReadOnly bool
+	// Cpus defaults to 1
+	Cpus int
+	// Memory defaults to 2Gb
+	Memory string
+}
+
+// DockerRun is a rendered container invocation
+type DockerRun struct {
+	// Argv starts with docker run
+	Argv []string
+	// Env is the environment of the docker process, NAME=value
+	Env     []string
+	Image   string
+	Command []string
+}
+
+// String quotes the invocation for a shell, env first
+func (r DockerRun) String() string {
+	var parts []string
+	for _, env := range r.Env {
+		parts = append(parts, shellQuote(env))
+	}
+	for _, arg := range r.Argv {
+		parts = append(parts, shellQuote(arg))
+	}
+	return strings.Join(parts, " ")
+}
+
+var shellSafePattern = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+func shellQuote(s string) string {
+	if shellSafePattern.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// UnresolvedPlaceholderError lists the config values, secrets and env
+// references a server needs but was not given
+type UnresolvedPlaceholderError struct {
+	Server       string
+	Placeholders []string
+}
+
+func (e *UnresolvedPlaceholderError) Error() string {
+	return fmt.Sprintf("server %s has unresolved placeholders: %s", e.Server, strings.Join(e.Placeholders, ", "))
+}
+
+// configTermPattern matches the gateway's {{path|functions}} terms
+var configTermPattern = regexp.MustCompile(`{{.*?}}`)
+
+// RenderDockerRun builds the docker run invocation the gateway uses for an
+// image server, following its clientpool argsAndEnv. Where the gateway would
+// substitute an empty value or <UNKNOWN>, rendering fails instead.
+func RenderDockerRun(server catalog.Server, opts RenderOptions) (*DockerRun, error) {
+	if server.Remote.URL != "" || server.SSEEndpoint != "" {
+		return nil, fmt.Errorf("server %s is a remote; the gateway starts no container", server.Name)
+	}
+	if server.Image == "" {
+		return nil, fmt.Errorf("server %s has no image", server.Name)
+	}
+	if opts.Cpus == 0 {
+		opts.Cpus = 1
+	}
+	if opts.Memory == "" {
+		opts.Memory = "2Gb"
+	}
+
+	args := []string{"run", "--rm", "-i", "--init", "--security-opt", "no-new-privileges",
+		"--cpus", fmt.Sprintf("%d", opts.Cpus), "--memory", opts.Memory, "--pull", "never",
+		"-l", "docker-mcp=true",
+		"-l", "docker-mcp-tool-type=mcp",
+		"-l", "docker-mcp-name=" + server.Name,
+		"-l", "docker-mcp-transport=stdio",
+	}
+	if server.DisableNetwork {
+		args = append(args, "--network", "none")
+	} else {
+		for _, network := range opts.Networks {
+			args = append(args, "--network", network)
+		}
+	}
+
+	var missingSecrets []string
+	spec, err := resolveContainer(server, opts.Config, func(secret catalog.Secret) string {
+		reference, ok := opts.Secrets[secret.Name]
+		if !ok {
+			missingSecrets = append(missingSecrets, "secret "+secret.Name)
+		}
+		return reference
+	})
+	if err != nil {
+		var unresolved *UnresolvedPlaceholderError
+		if errors.As(err, &unresolved) {
+			unresolved.Placeholders = append(missingSecrets, unresolved.Placeholders...)
+		}
+		return nil, err
+	}
+	if len(missingSecrets) > 0 {
+		return nil, &UnresolvedPlaceholderError{Server: server.Name, Placeholders: missingSecrets}
+	}
+
+	var env []string
+	for _, e := range spec.Env {
+		args = append(args, "-e", e.Name)
+		env = append(env, fmt.Sprintf("%s=%s", e.Name, e.Value))
+	}
+	for _, mount := range spec.Volumes {
+		// Long-lived containers are shared across tool calls, so never read-only
+		if !server.LongLived && opts.ReadOnly && !strings.HasSuffix(mount, ":ro") {
+			args = append(args, "-v", mount+":ro")
+		} else {
+			args = append(args, "-v", mount)
+		}
+	}
+	if spec.User != "" {
+		args = append(args, "-u", spec.User)
+	}
+	for _, host := range spec.ExtraHosts {
+		args = append(args, "--add-host", host)
+	}
+
+	argv := append([]string{"docker"}, args...)
+	argv = append(argv, server.Image)
+	argv = append(argv, spec.Command...)
+	return &DockerRun{Argv: argv, Env: env, Image: server.Image, Command: spec.Command}, nil
+}
+
+// containerSpec is an image server's container with its config and secrets
+// resolved. Env holds the secrets first, as -e flags are ordered by the
+// gateway, and leaves out empty values.
+type containerSpec struct {
+	Env        []catalog.Env
+	Secrets    int
+	Volumes    []string
+	User       string
+	ExtraHosts []string
+	Command    []string
+}
+
+// resolveContainer evaluates a server's env, volumes, user and command like
+// the gateway's clientpool argsAndEnv. Config is looked up in the gateway's
+// config.yaml shape; secretValue supplies each secret's value or reference,
+// which ${NAME} references expand to. Where the gateway would substitute an
+// empty value or <UNKNOWN>, an UnresolvedPlaceholderError is returned.
+func resolveContainer(server catalog.Server, config map[string]any, secretValue func(catalog.Secret) string) (containerSpec, error) {
+	// The gateway only exposes the server's own config, keyed by its
+	// canonical name
+	key := strings.ReplaceAll(server.Name, ".", "_")
+	r := &renderer{config: map[string]any{key: config[key]}}
+
+	var spec containerSpec
+	var env []string
+	for _, secret := range server.Secrets {
+		value := secretValue(secret)
+		spec.Env = append(spec.Env, catalog.Env{Name: secret.Env, Value: value})
+		env = append(env, fmt.Sprintf("%s=%s", secret.Env, value))
+	}
+	spec.Secrets = len(spec.Env)
+
+	for _, e := range server.Env {
+		var value string
+		if strings.Contains(e.Value, "{{") && strings.Contains(e.Value, "}}") {
+			value = fmt.Sprintf("%v", r.evaluate(e.Value))
+		} else {
+			value = r.expandEnv(e.Value, env)
+		}
+		if value != "" {
+			spec.Env = append(spec.Env, catalog.Env{Name: e.Name, Value: value})
+			env = append(env, fmt.Sprintf("%s=%s", e.Name, value))
+		}
+	}
+
+	for _, mount := range r.evaluateList(server.Volumes) {
+		if mount != "" {
+			spec.Volumes = append(spec.Volumes, mount)
+		}
+	}
+
+	if server.User != "" {
+		spec.User = server.User
+		if strings.Contains(spec.User, "{{") && strings.Contains(spec.User, "}}") {
+			spec.User = fmt.Sprintf("%v", r.evaluate(spec.User))
+		}
+	}
+
+	for _, host := range server.ExtraHosts {
+		if host != "" {
+			spec.ExtraHosts = append(spec.ExtraHosts, host)
+		}
+	}
+
+	for _, arg := range r.evaluateList(server.Command) {
+		spec.Command = append(spec.Command, r.expandEnv(arg, env))
+	}
+
+	if len(r.missing) > 0 {
+		return spec, &UnresolvedPlaceholderError{Server: server.Name, Placeholders: r.missing}
+	}
+	return spec, nil
+}
+
+// renderer evaluates values like the gateway, recording what is missing
+type renderer struct {
+	config  map[string]any
+	missing []string
+}
+
+func (r *renderer) evaluate(expression string) any {
+	r.check(expression)
+	return eval.Evaluate(expression, r.config)
+}
+
+func (r *renderer) evaluateList(expressions []string) []string {
+	for _, expression := range expressions {
+		r.check(expression)
+	}
+	return eval.EvaluateList(expressions, r.config)
+}
+
+// check records {{path}} terms with no config value. Terms with an or:
+// fallback always resolve.
+func (r *renderer) check(expression string) {
+	for _, term := range configTermPattern.FindAllString(expression, -1) {
+		path, functions, _ := strings.Cut(term[2:len(term)-2], "|")
+		if strings.Contains(functions, "or:") {
+			continue
+		}
+		if !hasConfigValue(r.config, strings.TrimSpace(path)) {
+			r.missing = append(r.missing, "config "+term)
+		}
+	}
+}
+
+// expandEnv expands $NAME and ${NAME} from the env built so far, as the
+// gateway does
+func (r *renderer) expandEnv(value string, env []string) string {
+	return os.Expand(value, func(name string) string {
+		for _, e := range env {
+			if after, ok := strings.CutPrefix(e, name+"="); ok {
+				return after
+			}
+		}
+		r.missing = append(r.missing, "env ${"+name+"}")
+		return ""
+	})
+}
+
+func hasConfigValue(config map[string]any, path string) bool {
+	top, rest, found := strings.Cut(path, ".")
+	value, ok := config[strings.TrimSpace(top)]
+	if !ok || value == nil {
+		return false
+	}
+	if !found {
+		return true
+	}
+	child, ok := value.(map[string]any)
+	return ok && hasConfigValue(child, rest)
+}
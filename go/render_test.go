@@ -0,0 +1,113 @@
+package catalogs
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+func TestRenderDockerRun(t *testing.T) {
+	server := catalog.Server{
+		Name:           "com.example/notes",
+		Type:           "server",
+		Image:          "docker.io/example/notes:1.0.0",
+		Secrets:        []catalog.Secret{{Name: "com.example/notes.api_key", Env: "API_KEY"}},
+		Env:            []catalog.Env{{Name: "REGION", Value: "{{com_example/notes.region}}"}, {Name: "EMPTY", Value: ""}},
+		Volumes:        []string{"{{com_example/notes.data}}:/data"},
+		User:           "1000:1000",
+		DisableNetwork: true,
+		ExtraHosts:     []string{"host.docker.internal:host-gateway"},
+		Command:        []string{"--key=${API_KEY}", "--log={{com_example/notes.log|or:info}}"},
+	}
+	run, err := RenderDockerRun(server, RenderOptions{
+		Config: map[string]any{
+			"com_example/notes": map[string]any{"region": "eu", "data": "/srv/notes"},
+			"other":             map[string]any{"region": "us"},
+		},
+		Secrets: map[string]string{"com.example/notes.api_key": "op://vault/notes/key"},
+	})
+	if err != nil {
+		t.Fatalf("RenderDockerRun failed: %v", err)
+	}
+
+	expected := []string{"docker", "run", "--rm", "-i", "--init", "--security-opt", "no-new-privileges",
+		"--cpus", "1", "--memory", "2Gb", "--pull", "never",
+		"-l", "docker-mcp=true", "-l", "docker-mcp-tool-type=mcp", "-l", "docker-mcp-name=com.example/notes", "-l", "docker-mcp-transport=stdio",
+		"--network", "none", "-e", "API_KEY", "-e", "REGION", "-v", "/srv/notes:/data", "-u", "1000:1000",
+		"--add-host", "host.docker.internal:host-gateway",
+		"docker.io/example/notes:1.0.0", "--key=op://vault/notes/key", "--log=info"}
+	if !reflect.DeepEqual(run.Argv, expected) {
+		t.Errorf("Unexpected argv\n got: %v\nwant: %v", run.Argv, expected)
+	}
+	if !reflect.DeepEqual(run.Env, []string{"API_KEY=op://vault/notes/key", "REGION=eu"}) {
+		t.Errorf("Unexpected env %v", run.Env)
+	}
+	if !strings.HasPrefix(run.String(), "API_KEY=op://vault/notes/key REGION=eu docker run ") {
+		t.Errorf("Unexpected shell line %s", run)
+	}
+}
+
+func TestRenderDockerRunOptions(t *testing.T) {
+	opts := RenderOptions{Networks: []string{"mcp"}, ReadOnly: true, Cpus: 2, Memory: "512Mb"}
+	for _, tc := range []struct {
+		name      string
+		longLived bool
+		want      []string
+		notWant   string
+	}{
+		{name: "short-lived", want: []string{"--cpus 2 --memory 512Mb", "--network mcp", "-v /srv/notes:/data:ro"}},
+		// Long-lived volumes are not read-only
+		{name: "long-lived", longLived: true, want: []string{"-v /srv/notes:/data"}, notWant: ":ro"},
+	} {
+		server := catalog.Server{Name: "com.example/notes", Type: "server", Image: "docker.io/example/notes:1.0.0", Volumes: []string{"/srv/notes:/data"}, LongLived: tc.longLived}
+		run, err := RenderDockerRun(server, opts)
+		if err != nil {
+			t.Fatalf("%s: RenderDockerRun failed: %v", tc.name, err)
+		}
+		line := strings.Join(run.Argv, " ")
+		for _, want := range tc.want {
+			if !strings.Contains(line, want) {
+				t.Errorf("%s: expected %q in %s", tc.name, want, line)
+			}
+		}
+		if tc.notWant != "" && strings.Contains(line, tc.notWant) {
+			t.Errorf("%s: unexpected %q in %s", tc.name, tc.notWant, line)
+		}
+	}
+}
+
+func TestRenderDockerRunUnresolved(t *testing.T) {
+	server := catalog.Server{
+		Name:    "com.example/notes",
+		Type:    "server",
+		Image:   "docker.io/example/notes:1.0.0",
+		Secrets: []catalog.Secret{{Name: "com.example/notes.api_key", Env: "API_KEY"}},
+		Env:     []catalog.Env{{Name: "REGION", Value: "{{com_example/notes.region}}"}, {Name: "HOME_DIR", Value: "${HOME_DIR_VALUE}"}},
+		Volumes: []string{"{{com_example/notes.data}}:/data"},
+	}
+
+	// Another server's config is not visible
+	_, err := RenderDockerRun(server, RenderOptions{Config: map[string]any{"other": map[string]any{"region": "us"}}})
+	var unresolved *UnresolvedPlaceholderError
+	if !errors.As(err, &unresolved) {
+		t.Fatalf("Expected UnresolvedPlaceholderError, got %v", err)
+	}
+	expected := []string{"secret com.example/notes.api_key", "config {{com_example/notes.region}}",
+		"env ${HOME_DIR_VALUE}", "config {{com_example/notes.data}}"}
+	if !reflect.DeepEqual(unresolved.Placeholders, expected) {
+		t.Errorf("Unexpected placeholders %v", unresolved.Placeholders)
+	}
+}
+
+func TestRenderDockerRunErrors(t *testing.T) {
+	remote := catalog.Server{Name: "com-example-remote", Remote: catalog.Remote{URL: "https://mcp.example.com/mcp"}}
+	if _, err := RenderDockerRun(remote, RenderOptions{}); err == nil {
+		t.Error("Expected error for a remote")
+	}
+	if _, err := RenderDockerRun(catalog.Server{Name: "com-example-poci"}, RenderOptions{}); err == nil {
+		t.Error("Expected error for a server without an image")
+	}
+}
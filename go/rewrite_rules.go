@@ -0,0 +1,451 @@
+package catalogs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/PaesslerAG/gval"
+	"github.com/PaesslerAG/jsonpath"
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	v0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"gopkg.in/yaml.v3"
+)
+
+// Rewrite rule targets and actions
+const (
+	RuleOnInput  = "input"
+	RuleOnOutput = "output"
+
+	RuleActionSet      = "set"
+	RuleActionDelete   = "delete"
+	RuleActionRename   = "rename"
+	RuleActionTemplate = "template"
+)
+
+// RewriteRule changes the registry input or the catalog output without Go
+// code. Select is a JSONPath ending in a member name or index; When is an
+// optional gval condition over value, key, parent and root.
+type RewriteRule struct {
+	Name   string `json:"name" yaml:"name"`
+	On     string `json:"on,omitempty" yaml:"on,omitempty"`
+	Select string `json:"select" yaml:"select"`
+	When   string `json:"when,omitempty" yaml:"when,omitempty"`
+	Action string `json:"action" yaml:"action"`
+	// Value is the new value for set
+	Value any `json:"value,omitempty" yaml:"value,omitempty"`
+	// To is the new member name for rename
+	To string `json:"to,omitempty" yaml:"to,omitempty"`
+	// Template is a text/template rendered with value, key, parent and root
+	Template string `json:"template,omitempty" yaml:"template,omitempty"`
+
+	parent   gval.Evaluable
+	path     []ruleSegment
+	definite bool
+	member   string
+	index    int
+	when     gval.Evaluable
+	template *template.Template
+}
+
+// ruleSegment is one member name or index of a definite path
+type ruleSegment struct {
+	member string
+	index  int
+}
+
+func (s ruleSegment) String() string {
+	if s.member != "" {
+		return s.member
+	}
+	return fmt.Sprintf("[%d]", s.index)
+}
+
+// RewriteRules is a parsed rules file
+type RewriteRules struct {
+	Rules []*RewriteRule `json:"rules" yaml:"rules"`
+}
+
+// RuleApplication records one change made by a rule
+type RuleApplication struct {
+	Rule   string
+	On     string
+	Action string
+	Select string
+}
+
+func (a RuleApplication) String() string {
+	return fmt.Sprintf("rule %s: %s %s on %s", a.Rule, a.Action, a.Select, a.On)
+}
+
+var (
+	ruleMemberPattern = regexp.MustCompile(`^(.*)(?:\.([A-Za-z_][A-Za-z0-9_-]*)|\[['"]([^'"]+)['"]\]|\[(-?\d+)\])$`)
+	ruleLanguage      = gval.Full(jsonpath.Language())
+)
+
+// ParseRewriteRules parses and compiles a YAML or JSON rules file
+func ParseRewriteRules(data []byte) (*RewriteRules, error) {
+	var rules RewriteRules
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return nil, err
+	}
+	for i, rule := range rules.Rules {
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("#%d", i+1)
+		}
+		if err := rule.compile(); err != nil {
+			return nil, fmt.Errorf("rule %s: %w", rule.Name, err)
+		}
+	}
+	return &rules, nil
+}
+
+// LoadRewriteRules reads a rules file
+func LoadRewriteRules(file string) (*RewriteRules, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := ParseRewriteRules(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse rules %s: %w", file, err)
+	}
+	return rules, nil
+}
+
+func (r *RewriteRule) compile() error {
+	switch r.On {
+	case "":
+		r.On = RuleOnOutput
+	case RuleOnInput, RuleOnOutput:
+	default:
+		return fmt.Errorf("on must be %s or %s, got %q", RuleOnInput, RuleOnOutput, r.On)
+	}
+
+	match := ruleMemberPattern.FindStringSubmatch(strings.TrimSpace(r.Select))
+	if match == nil || match[1] == "" {
+		return fmt.Errorf("select %q must end in a member name or index", r.Select)
+	}
+	var err error
+	r.definite = definitePath(match[1])
+	if r.definite {
+		// Definite paths are walked here: jsonpath reports an absent member
+		// or index as an error, where a rule should simply not match
+		var ok bool
+		if r.path, ok = pathSegments(match[1]); !ok {
+			return fmt.Errorf("select %q must be member names and indexes, or use wildcards or filters", r.Select)
+		}
+	} else if r.parent, err = jsonpath.New(match[1]); err != nil {
+		return fmt.Errorf("invalid select %q: %w", r.Select, err)
+	}
+	r.index = -1
+	switch {
+	case match[2] != "":
+		r.member = match[2]
+	case match[3] != "":
+		r.member = match[3]
+	default:
+		if r.index, err = strconv.Atoi(match[4]); err != nil || r.index < 0 {
+			return fmt.Errorf("select %q must not end in a negative index", r.Select)
+		}
+	}
+
+	if r.When != "" {
+		if r.when, err = ruleLanguage.NewEvaluable(r.When); err != nil {
+			return fmt.Errorf("invalid when %q: %w", r.When, err)
+		}
+	}
+
+	switch r.Action {
+	case RuleActionSet, RuleActionDelete:
+	case RuleActionRename:
+		if r.member == "" || r.To == "" {
+			return fmt.Errorf("rename needs a select ending in a member name and a to")
+		}
+		if r.To == r.member {
+			return fmt.Errorf("rename to %q keeps the same name", r.To)
+		}
+	case RuleActionTemplate:
+		if r.template, err = template.New(r.Name).Option("missingkey=error").Parse(r.Template); err != nil {
+			return fmt.Errorf("invalid template: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported action %q", r.Action)
+	}
+	return nil
+}
+
+// definitePath reports whether a JSONPath selects at most one node, in which
+// case jsonpath returns the node itself rather than a list of matches
+func definitePath(path string) bool {
+	if strings.Contains(path, "..") || strings.Contains(path, "*") || strings.Contains(path, "?") {
+		return false
+	}
+	depth := 0
+	for _, c := range path {
+		switch c {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ',', ':':
+			if depth > 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// pathSegments splits a definite path into its member names and indexes
+func pathSegments(path string) ([]ruleSegment, bool) {
+	var segments []ruleSegment
+	for path != "$" {
+		match := ruleMemberPattern.FindStringSubmatch(path)
+		if match == nil {
+			return nil, false
+		}
+		segment := ruleSegment{member: match[2] + match[3]}
+		if segment.member == "" {
+			segment.index, _ = strconv.Atoi(match[4])
+		}
+		segments = append([]ruleSegment{segment}, segments...)
+		path = match[1]
+	}
+	return segments, true
+}
+
+// walk follows a definite path from the root. It reports false when a
+// member or index is absent, and an error when a node has the wrong type.
+func walk(root any, path []ruleSegment) (any, bool, error) {
+	node := root
+	for _, segment := range path {
+		switch n := node.(type) {
+		case map[string]any:
+			if segment.member == "" {
+				return nil, false, fmt.Errorf("cannot select %s from an object", segment)
+			}
+			value, ok := n[segment.member]
+			if !ok {
+				return nil, false, nil
+			}
+			node = value
+		case []any:
+			if segment.member != "" {
+				return nil, false, fmt.Errorf("cannot select %s from an array", segment)
+			}
+			index := segment.index
+			if index < 0 {
+				index += len(n)
+			}
+			if index < 0 || index >= len(n) {
+				return nil, false, nil
+			}
+			node = n[index]
+		default:
+			return nil, false, fmt.Errorf("cannot select %s from a %T", segment, node)
+		}
+	}
+	return node, true, nil
+}
+
+// ruleDeleted marks array items removed by a delete rule until the document
+// is swept, so indexes stay valid while a rule runs
+type ruleDeleted struct{}
+
+// Apply runs the rules for one side (input or output) over a JSON document
+func (rs *RewriteRules) Apply(on string, doc any) (any, []RuleApplication, error) {
+	var applied []RuleApplication
+	for _, rule := range rs.Rules {
+		if rule.On != on {
+			continue
+		}
+		changes, err := rule.apply(doc)
+		if err != nil {
+			return nil, nil, fmt.Errorf("rule %s: %w", rule.Name, err)
+		}
+		doc = sweepDeleted(doc)
+		applied = append(applied, changes...)
+	}
+	return doc, applied, nil
+}
+
+func (r *RewriteRule) apply(root any) ([]RuleApplication, error) {
+	var parents []any
+	if r.definite {
+		parent, ok, err := walk(root, r.path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate match: %w", err)
+		}
+		if !ok {
+			// A rule that does not match simply does nothing
+			return nil, nil
+		}
+		parents = []any{parent}
+	} else {
+		result, err := r.parent(context.Background(), root)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate match: %w", err)
+		}
+		parents, _ = result.([]any)
+	}
+
+	var applied []RuleApplication
+	for _, parent := range parents {
+		key, value, ok := r.locate(parent)
+		if !ok && r.Action != RuleActionSet && r.Action != RuleActionTemplate {
+			continue
+		}
+		if r.when != nil {
+			env := map[string]any{"value": value, "key": key, "parent": parent, "root": root}
+			match, err := r.when.EvalBool(context.Background(), env)
+			if err != nil {
+				return nil, fmt.Errorf("failed to evaluate when: %w", err)
+			}
+			if !match {
+				continue
+			}
+		}
+
+		switch r.Action {
+		case RuleActionSet:
+			if !r.store(parent, jsonCompatible(r.Value)) {
+				continue
+			}
+		case RuleActionTemplate:
+			var out bytes.Buffer
+			env := map[string]any{"value": value, "key": key, "parent": parent, "root": root}
+			if err := r.template.Execute(&out, env); err != nil {
+				return nil, fmt.Errorf("failed to render template: %w", err)
+			}
+			if !r.store(parent, out.String()) {
+				continue
+			}
+		case RuleActionDelete:
+			if m, isMap := parent.(map[string]any); isMap {
+				delete(m, r.member)
+			} else {
+				parent.([]any)[r.index] = ruleDeleted{}
+			}
+		case RuleActionRename:
+			m := parent.(map[string]any)
+			if _, exists := m[r.To]; exists {
+				return nil, fmt.Errorf("rename of %s would overwrite the existing %s", r.member, r.To)
+			}
+			delete(m, r.member)
+			m[r.To] = value
+		}
+		applied = append(applied, RuleApplication{Rule: r.Name, On: r.On, Action: r.Action, Select: r.Select})
+	}
+	return applied, nil
+}
+
+// locate returns the selected member or index of a parent node
+func (r *RewriteRule) locate(parent any) (any, any, bool) {
+	switch p := parent.(type) {
+	case map[string]any:
+		if r.member == "" {
+			return nil, nil, false
+		}
+		value, ok := p[r.member]
+		return r.member, value, ok
+	case []any:
+		if r.index < 0 || r.index >= len(p) {
+			return nil, nil, false
+		}
+		return r.index, p[r.index], true
+	}
+	return nil, nil, false
+}
+
+// store sets the selected member or index, reporting whether it could
+func (r *RewriteRule) store(parent any, value any) bool {
+	switch p := parent.(type) {
+	case map[string]any:
+		if r.member == "" {
+			return false
+		}
+		p[r.member] = value
+		return true
+	case []any:
+		if r.index < 0 || r.index >= len(p) {
+			return false
+		}
+		p[r.index] = value
+		return true
+	}
+	return false
+}
+
+func sweepDeleted(doc any) any {
+	switch d := doc.(type) {
+	case map[string]any:
+		for key, value := range d {
+			d[key] = sweepDeleted(value)
+		}
+	case []any:
+		kept := d[:0]
+		for _, item := range d {
+			if _, deleted := item.(ruleDeleted); !deleted {
+				kept = append(kept, sweepDeleted(item))
+			}
+		}
+		return kept
+	}
+	return doc
+}
+
+// rewriteInput applies the input rules to a registry entry
+func (rs *RewriteRules) rewriteInput(response v0.ServerResponse) (v0.ServerResponse, []RuleApplication, error) {
+	var doc any
+	if err := roundTripJSON(response, &doc); err != nil {
+		return response, nil, err
+	}
+	doc, applied, err := rs.Apply(RuleOnInput, doc)
+	if err != nil || len(applied) == 0 {
+		return response, applied, err
+	}
+	var rewritten v0.ServerResponse
+	if err := roundTripJSON(doc, &rewritten); err != nil {
+		return response, nil, fmt.Errorf("input rules do not produce a valid registry entry: %w", err)
+	}
+	return rewritten, applied, nil
+}
+
+// rewriteOutput applies the output rules to a catalog server in place
+func (rs *RewriteRules) rewriteOutput(server *catalog.Server) ([]RuleApplication, error) {
+	var doc any
+	if err := roundTripJSON(server, &doc); err != nil {
+		return nil, err
+	}
+	doc, applied, err := rs.Apply(RuleOnOutput, doc)
+	if err != nil || len(applied) == 0 {
+		return applied, err
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	var rewritten catalog.Server
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&rewritten); err != nil {
+		return nil, fmt.Errorf("output rules do not produce a valid catalog server: %w", err)
+	}
+	*server = rewritten
+	return applied, nil
+}
+
+func roundTripJSON(in any, out any) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
@@ -0,0 +1,236 @@
+package catalogs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+const rewriteRegistryJSON = `{
+	"server": {
+		"name": "com.example/notes",
+		"description": "Notes server",
+		"version": "1.0.0",
+		"remotes": [{
+			"type": "streamable-http",
+			"url": "https://mcp.example.com/mcp",
+			"headers": [{
+				"name": "Authorization",
+				"value": "Bearer {token}",
+				"variables": {"token": {"description": "API token"}}
+			}]
+		}]
+	}
+}`
+
+func TestRewriteRulesReclassifyHeader(t *testing.T) {
+	rules, err := ParseRewriteRules([]byte(`
+rules:
+  - name: token-is-secret
+    on: input
+    select: $.server.remotes[*].headers[?(@.name == "Authorization")].variables.token.isSecret
+    action: set
+    value: true
+`))
+	if err != nil {
+		t.Fatalf("ParseRewriteRules failed: %v", err)
+	}
+
+	result, err := TransformJSONWithOptions(rewriteRegistryJSON, TransformOptions{Rewrites: rules})
+	if err != nil {
+		t.Fatalf("TransformJSONWithOptions failed: %v", err)
+	}
+
+	if got := result.Server.Remote.Headers["Authorization"]; got != "Bearer ${TOKEN}" {
+		t.Errorf("Expected header to use a secret, got %q", got)
+	}
+	if len(result.Rewrites) != 1 || result.Rewrites[0].Rule != "token-is-secret" || result.Rewrites[0].On != RuleOnInput {
+		t.Errorf("Unexpected rewrites %+v", result.Rewrites)
+	}
+}
+
+func TestRewriteRulesForceImage(t *testing.T) {
+	rules, err := ParseRewriteRules([]byte(`
+rules:
+  - name: mirror-image
+    select: $.image
+    when: value =~ "^docker.io/"
+    action: template
+    template: 'mirror.example.com/{{ .value }}'
+  - select: $.image
+    when: value =~ "^ghcr.io/"
+    action: set
+    value: never-applied
+`))
+	if err != nil {
+		t.Fatalf("ParseRewriteRules failed: %v", err)
+	}
+
+	server := catalog.Server{Name: "com-example-notes", Type: "server", Image: "docker.io/example/notes:1.0.0"}
+	applied, err := rules.rewriteOutput(&server)
+	if err != nil {
+		t.Fatalf("rewriteOutput failed: %v", err)
+	}
+
+	if server.Image != "mirror.example.com/docker.io/example/notes:1.0.0" {
+		t.Errorf("Unexpected image %q", server.Image)
+	}
+	if len(applied) != 1 || applied[0].Rule != "mirror-image" {
+		t.Errorf("Unexpected rewrites %+v", applied)
+	}
+}
+
+func TestRewriteRulesDeleteAndRename(t *testing.T) {
+	rules, err := ParseRewriteRules([]byte(`
+rules:
+  - select: $.env[?(@.name == "DEBUG")].value
+    action: set
+    value: "false"
+  - select: $.volumes[0]
+    action: delete
+  - select: $.title
+    action: rename
+    to: description
+`))
+	if err != nil {
+		t.Fatalf("ParseRewriteRules failed: %v", err)
+	}
+
+	server := catalog.Server{
+		Name:    "com-example-notes",
+		Type:    "server",
+		Title:   "Notes",
+		Image:   "docker.io/example/notes:1.0.0",
+		Volumes: []string{"/data:/data"},
+		Env:     []catalog.Env{{Name: "UNITS", Value: "metric"}, {Name: "DEBUG", Value: "true"}},
+	}
+	if _, err := rules.rewriteOutput(&server); err != nil {
+		t.Fatalf("rewriteOutput failed: %v", err)
+	}
+
+	if server.Env[1].Value != "false" || server.Env[0].Value != "metric" {
+		t.Errorf("Expected only DEBUG to change, got %+v", server.Env)
+	}
+	if len(server.Volumes) != 0 {
+		t.Errorf("Expected volume to be deleted, got %v", server.Volumes)
+	}
+	if server.Title != "" || server.Description != "Notes" {
+		t.Errorf("Expected title renamed to description, got %q / %q", server.Title, server.Description)
+	}
+}
+
+func TestRewriteRulesRenameOverwrite(t *testing.T) {
+	rules, err := ParseRewriteRules([]byte(`
+rules:
+  - select: $.title
+    action: rename
+    to: description
+`))
+	if err != nil {
+		t.Fatalf("ParseRewriteRules failed: %v", err)
+	}
+
+	server := catalog.Server{Name: "com-example-notes", Type: "server", Title: "Notes", Description: "Notes server", Image: "docker.io/example/notes:1.0.0"}
+	_, err = rules.rewriteOutput(&server)
+	if err == nil || !strings.Contains(err.Error(), "would overwrite the existing description") {
+		t.Errorf("Expected the rename to be rejected, got %v", err)
+	}
+	if server.Description != "Notes server" {
+		t.Errorf("Expected description to be kept, got %q", server.Description)
+	}
+}
+
+func TestRewriteRulesNoMatch(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		path string
+	}{
+		{name: "unknown key", path: "$.remote.headers.Authorization"},
+		{name: "missing index", path: "$.volumes[3]"},
+		{name: "missing parent index", path: "$.volumes[3].name"},
+		{name: "missing parent key", path: "$.env[0].value"},
+	} {
+		rules, err := ParseRewriteRules([]byte("rules:\n  - {select: '" + tc.path + "', action: delete}\n"))
+		if err != nil {
+			t.Fatalf("%s: ParseRewriteRules failed: %v", tc.name, err)
+		}
+
+		server := catalog.Server{Name: "com-example-notes", Type: "server", Image: "docker.io/example/notes:1.0.0", Volumes: []string{"/data:/data"}}
+		applied, err := rules.rewriteOutput(&server)
+		if err != nil {
+			t.Fatalf("%s: rewriteOutput failed: %v", tc.name, err)
+		}
+		if len(applied) != 0 {
+			t.Errorf("%s: expected no rewrites, got %+v", tc.name, applied)
+		}
+	}
+}
+
+func TestRewriteRulesMatchError(t *testing.T) {
+	// Selecting into a string is a broken rule, not a rule that did not match
+	rules, err := ParseRewriteRules([]byte(`
+rules:
+  - on: input
+    select: $.server.name.first.last
+    action: delete
+`))
+	if err != nil {
+		t.Fatalf("ParseRewriteRules failed: %v", err)
+	}
+
+	_, err = TransformJSONWithOptions(rewriteRegistryJSON, TransformOptions{Rewrites: rules})
+	if err == nil || !strings.Contains(err.Error(), "cannot select first from a string") {
+		t.Errorf("Expected a select error, got %v", err)
+	}
+}
+
+func TestRewriteRulesInvalidOutput(t *testing.T) {
+	rules, err := ParseRewriteRules([]byte(`
+rules:
+  - select: $.imag
+    action: set
+    value: typo
+`))
+	if err != nil {
+		t.Fatalf("ParseRewriteRules failed: %v", err)
+	}
+
+	server := catalog.Server{Name: "com-example-notes", Type: "server", Image: "docker.io/example/notes:1.0.0"}
+	if _, err := rules.rewriteOutput(&server); err == nil || !strings.Contains(err.Error(), "valid catalog server") {
+		t.Errorf("Expected invalid catalog server error, got %v", err)
+	}
+}
+
+func TestParseRewriteRulesErrors(t *testing.T) {
+	for name, doc := range map[string]string{
+		"on":               "rules:\n  - {select: $.image, on: both, action: delete}",
+		"select":           "rules:\n  - {select: '$.env[*]', action: delete}",
+		"action":           "rules:\n  - {select: $.image, action: upsert}",
+		"rename":           "rules:\n  - {select: $.image, action: rename}",
+		"rename to itself": "rules:\n  - {select: $.image, action: rename, to: image}",
+		"when":             "rules:\n  - {select: $.image, when: 'value ==', action: delete}",
+		"template":         "rules:\n  - {select: $.image, action: template, template: '{{ .value'}",
+	} {
+		if _, err := ParseRewriteRules([]byte(doc)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestLoadRewriteRules(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "rules.yaml")
+	if err := os.WriteFile(file, []byte("rules:\n  - {name: drop-user, select: $.user, action: delete}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	rules, err := LoadRewriteRules(file)
+	if err != nil {
+		t.Fatalf("LoadRewriteRules failed: %v", err)
+	}
+	if len(rules.Rules) != 1 || rules.Rules[0].Name != "drop-user" || rules.Rules[0].On != RuleOnOutput {
+		t.Errorf("Unexpected rules %+v", rules.Rules)
+	}
+}
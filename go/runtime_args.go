@@ -0,0 +1,162 @@
+package catalogs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// runtimeArgs is what docker run runtime arguments contribute to a catalog server
+type runtimeArgs struct {
+	User           string
+	Volumes        []string
+	Env            []catalog.Env
+	DisableNetwork bool
+	ExtraHosts     []string
+	// Unsupported lists flags with no matching catalog.Server field
+	Unsupported []string
+}
+
+// impliedRuntimeFlags are always passed by the gateway, so they carry no information
+var impliedRuntimeFlags = map[string]bool{
+	"--rm":          true,
+	"-i":            true,
+	"--interactive": true,
+	"--init":        true,
+	"-t":            true,
+	"--tty":         true,
+	"-it":           true,
+	"--pull":        true,
+}
+
+// runtimeFlag is one flag with its value, e.g. ("--network", "none")
+type runtimeFlag struct {
+	Name  string
+	Value string
+}
+
+func (f runtimeFlag) String() string {
+	if f.Value == "" {
+		return f.Name
+	}
+	return fmt.Sprintf("%s %s", f.Name, f.Value)
+}
+
+// splitRuntimeFlags turns runtime arguments into flags. Named arguments carry
+// the flag in Name; positional arguments may hold "--flag=value" or "--flag value".
+func splitRuntimeFlags(args []model.Argument) (flags []runtimeFlag, positional []string) {
+	for _, arg := range args {
+		value := arg.Value
+		if len(arg.Variables) > 0 {
+			value = restoreInterpolatedValue(value, arg.Variables)
+		}
+
+		name := arg.Name
+		if arg.Type != model.ArgumentTypeNamed {
+			if !strings.HasPrefix(value, "-") {
+				positional = append(positional, value)
+				continue
+			}
+			name, value = value, ""
+			if i := strings.IndexAny(name, " ="); i > 0 {
+				name, value = name[:i], strings.TrimSpace(name[i+1:])
+			}
+		} else if i := strings.Index(name, "="); i > 0 && value == "" {
+			name, value = name[:i], name[i+1:]
+		}
+
+		flags = append(flags, runtimeFlag{Name: name, Value: value})
+	}
+	return flags, positional
+}
+
+// afterEquals returns the part of value after the first '=', if any
+func afterEquals(value string) string {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) == 2 {
+		return parts[1]
+	}
+	return value
+}
+
+// parseMount converts a --mount spec to the simple src:dst volume format
+// Input: "type=bind,src={{source_path}},dst={{target_path}}"
+// Output: "{{source_path}}:{{target_path}}"
+func parseMount(value string) string {
+	var src, dst string
+	parts := strings.Split(value, ",")
+	for _, part := range parts {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) == 2 {
+			switch kv[0] {
+			case "src", "source":
+				src = kv[1]
+			case "dst", "destination", "target":
+				dst = kv[1]
+			}
+		}
+	}
+	if src != "" && dst != "" {
+		return fmt.Sprintf("%s:%s", src, dst)
+	}
+	// Fallback to full value if parsing fails
+	return value
+}
+
+// parseRuntimeArgs interprets docker run runtime arguments. Flags that map
+// onto catalog.Server fields are applied, flags the gateway always passes are
+// dropped and everything else is reported as unsupported.
+func parseRuntimeArgs(args []model.Argument) runtimeArgs {
+	var result runtimeArgs
+
+	flags, positional := splitRuntimeFlags(args)
+	for _, flag := range flags {
+		switch flag.Name {
+		case "-u", "--user":
+			if result.User == "" {
+				result.User = afterEquals(flag.Value)
+			}
+		case "-v", "--volume":
+			result.Volumes = append(result.Volumes, afterEquals(flag.Value))
+		case "--mount":
+			result.Volumes = append(result.Volumes, parseMount(flag.Value))
+		case "-e", "--env":
+			name, value, ok := strings.Cut(flag.Value, "=")
+			if !ok {
+				// -e NAME passes the host's value through, which the catalog can't express
+				result.Unsupported = append(result.Unsupported, flag.String())
+				continue
+			}
+			result.Env = append(result.Env, catalog.Env{Name: name, Value: value})
+		case "--network", "--net":
+			if flag.Value == "none" {
+				result.DisableNetwork = true
+			} else {
+				result.Unsupported = append(result.Unsupported, flag.String())
+			}
+		case "--add-host":
+			result.ExtraHosts = append(result.ExtraHosts, flag.Value)
+		default:
+			if !impliedRuntimeFlags[flag.Name] {
+				result.Unsupported = append(result.Unsupported, flag.String())
+			}
+		}
+	}
+
+	for _, value := range positional {
+		result.Unsupported = append(result.Unsupported, value)
+	}
+
+	return result
+}
+
+// unsupportedRuntimeArgWarnings describes runtime arguments dropped by the conversion
+func unsupportedRuntimeArgWarnings(parsed runtimeArgs) []string {
+	var warnings []string
+	for _, flag := range parsed.Unsupported {
+		warnings = append(warnings, fmt.Sprintf("unsupported runtime argument %q has no catalog equivalent and was dropped", flag))
+	}
+	return warnings
+}
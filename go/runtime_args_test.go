@@ -0,0 +1,112 @@
+package catalogs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+func namedArg(name, value string) model.Argument {
+	return model.Argument{
+		Type:               model.ArgumentTypeNamed,
+		Name:               name,
+		InputWithVariables: model.InputWithVariables{Input: model.Input{Value: value}},
+	}
+}
+
+func positionalArg(value string) model.Argument {
+	return model.Argument{
+		Type:               model.ArgumentTypePositional,
+		InputWithVariables: model.InputWithVariables{Input: model.Input{Value: value}},
+	}
+}
+
+func TestParseRuntimeArgs(t *testing.T) {
+	parsed := parseRuntimeArgs([]model.Argument{
+		namedArg("-u", "1000:1000"),
+		namedArg("-v", "/data:/data"),
+		namedArg("--mount", "type=bind,src=/src,dst=/dst"),
+		namedArg("-e", "LOG_LEVEL=debug"),
+		namedArg("--network", "none"),
+		namedArg("--add-host", "host.docker.internal:host-gateway"),
+		namedArg("--rm", ""),
+		positionalArg("-i"),
+		namedArg("--read-only", ""),
+		positionalArg("--tmpfs=/tmp"),
+		namedArg("--cap-drop", "ALL"),
+		namedArg("--workdir", "/app"),
+		namedArg("--entrypoint", "/bin/server"),
+		positionalArg("--memory 512m"),
+	})
+
+	if parsed.User != "1000:1000" {
+		t.Errorf("Expected user '1000:1000', got '%s'", parsed.User)
+	}
+	if len(parsed.Volumes) != 2 || parsed.Volumes[0] != "/data:/data" || parsed.Volumes[1] != "/src:/dst" {
+		t.Errorf("Unexpected volumes: %v", parsed.Volumes)
+	}
+	if len(parsed.Env) != 1 || parsed.Env[0].Name != "LOG_LEVEL" || parsed.Env[0].Value != "debug" {
+		t.Errorf("Unexpected env: %+v", parsed.Env)
+	}
+	if !parsed.DisableNetwork {
+		t.Error("Expected --network none to disable the network")
+	}
+	if len(parsed.ExtraHosts) != 1 || parsed.ExtraHosts[0] != "host.docker.internal:host-gateway" {
+		t.Errorf("Unexpected extra hosts: %v", parsed.ExtraHosts)
+	}
+
+	expected := []string{"--read-only", "--tmpfs /tmp", "--cap-drop ALL", "--workdir /app", "--entrypoint /bin/server", "--memory 512m"}
+	if strings.Join(parsed.Unsupported, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected unsupported %v, got %v", expected, parsed.Unsupported)
+	}
+}
+
+func TestParseRuntimeArgsNetwork(t *testing.T) {
+	parsed := parseRuntimeArgs([]model.Argument{namedArg("--network=host", "")})
+
+	if parsed.DisableNetwork {
+		t.Error("Expected --network host not to disable the network")
+	}
+	if len(parsed.Unsupported) != 1 || parsed.Unsupported[0] != "--network host" {
+		t.Errorf("Expected --network host to be unsupported, got %v", parsed.Unsupported)
+	}
+}
+
+func TestTransformReportsUnsupportedRuntimeArgs(t *testing.T) {
+	registryJSON := `{
+		"server": {
+			"name": "io.github.example/sandboxed",
+			"description": "Sandboxed server",
+			"version": "1.0.0",
+			"packages": [
+				{
+					"registryType": "oci",
+					"identifier": "docker.io/example/sandboxed",
+					"version": "1.0.0",
+					"transport": {"type": "stdio"},
+					"runtimeArguments": [
+						{"type": "named", "name": "--network", "value": "none"},
+						{"type": "named", "name": "-e", "value": "MODE={mode}", "variables": {"mode": {"default": "fast"}}},
+						{"type": "named", "name": "--read-only"}
+					]
+				}
+			]
+		}
+	}`
+
+	result, err := TransformJSONWithOptions(registryJSON, TransformOptions{})
+	if err != nil {
+		t.Fatalf("TransformJSONWithOptions failed: %v", err)
+	}
+
+	if !result.Server.DisableNetwork {
+		t.Error("Expected disableNetwork to be set")
+	}
+	if len(result.Server.Env) != 1 || result.Server.Env[0].Value != "{{mode}}" {
+		t.Errorf("Expected MODE env with interpolation, got %+v", result.Server.Env)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "--read-only") {
+		t.Errorf("Expected a warning for --read-only, got %v", result.Warnings)
+	}
+}
@@ -0,0 +1,201 @@
+package catalogs
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+// SecretScanMode controls what the transform does with hardcoded secrets.
+type SecretScanMode string
+
+const (
+	SecretScanOff  SecretScanMode = "off"
+	SecretScanWarn SecretScanMode = "warn"
+	// SecretScanDeny fails the transform so that no output is written.
+	SecretScanDeny SecretScanMode = "deny"
+)
+
+// ParseSecretScanMode parses a -secret-scan flag value.
+func ParseSecretScanMode(s string) (SecretScanMode, error) {
+	switch mode := SecretScanMode(s); mode {
+	case SecretScanOff, SecretScanWarn, SecretScanDeny:
+		return mode, nil
+	}
+	return "", fmt.Errorf("invalid secret scan mode %q (expected off, warn or deny)", s)
+}
+
+// SecretFinding is a value that looks like a hardcoded secret.
+type SecretFinding struct {
+	// Path locates the value, e.g. env[0].value or remote.headers.Authorization.
+	Path string `json:"path"`
+	Rule string `json:"rule"`
+	// Redacted shows only the first characters of the value.
+	Redacted string `json:"redacted"`
+}
+
+func (f SecretFinding) String() string {
+	return fmt.Sprintf("possible hardcoded secret at %s (%s): %s", f.Path, f.Rule, f.Redacted)
+}
+
+// SecretsFoundError is returned when SecretScanDeny finds secrets.
+type SecretsFoundError struct {
+	Findings []SecretFinding
+}
+
+func (e *SecretsFoundError) Error() string {
+	lines := make([]string, 0, len(e.Findings))
+	for _, finding := range e.Findings {
+		lines = append(lines, finding.String())
+	}
+	return fmt.Sprintf("refusing to write output with %d hardcoded secret(s):\n  %s", len(e.Findings), strings.Join(lines, "\n  "))
+}
+
+type secretRule struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+// Known token formats. These apply to every string, wherever it appears.
+var secretRules = []secretRule{
+	{"google-oauth-client-secret", regexp.MustCompile(`GOCSPX-[0-9A-Za-z_-]{28}`)},
+	{"google-api-key", regexp.MustCompile(`AIza[0-9A-Za-z_-]{35}`)},
+	{"github-pat", regexp.MustCompile(`ghp_[0-9A-Za-z]{36}`)},
+	{"github-fine-grained-pat", regexp.MustCompile(`github_pat_[0-9A-Za-z_]{82}`)},
+	{"github-oauth", regexp.MustCompile(`gh[ousr]_[0-9A-Za-z]{36}`)},
+	{"aws-access-key-id", regexp.MustCompile(`\b(A3T[A-Z0-9]|AKIA|AGPA|AIDA|AROA|AIPA|ANPA|ANVA|ASIA)[A-Z0-9]{16}\b`)},
+	{"slack-token", regexp.MustCompile(`xox[baprs]-[0-9A-Za-z-]{10,}`)},
+	{"private-key", regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)},
+}
+
+const (
+	minEntropyTokenLength = 20
+	minEntropyBits        = 4.0
+)
+
+// placeholderPattern matches {{config}}, ${SECRET} and {variable} interpolation.
+var placeholderPattern = regexp.MustCompile(`\{\{[^}]*\}\}|\$\{[^}]*\}|\{[^}]*\}`)
+
+func shannonEntropy(s string) float64 {
+	counts := make(map[rune]int)
+	for _, r := range s {
+		counts[r]++
+	}
+	var entropy float64
+	n := float64(len([]rune(s)))
+	for _, count := range counts {
+		p := float64(count) / n
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+var hexPattern = regexp.MustCompile(`^[0-9a-fA-F]+$`)
+
+// highEntropyToken returns the first token of s that looks like random key
+// material. Interpolation placeholders, URL hosts and paths, and hex strings
+// such as digests are ignored.
+func highEntropyToken(s string) string {
+	s = placeholderPattern.ReplaceAllString(s, " ")
+	tokens := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ' ' || r == '\t' || r == '=' || r == ':' || r == ',' || r == ';' || r == '"' || r == '\''
+	})
+	for _, token := range tokens {
+		if len(token) < minEntropyTokenLength || strings.HasPrefix(token, "//") || hexPattern.MatchString(token) {
+			continue
+		}
+		hasLetter := strings.ContainsAny(token, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
+		hasDigit := strings.ContainsAny(token, "0123456789")
+		if hasLetter && hasDigit && shannonEntropy(token) >= minEntropyBits {
+			return token
+		}
+	}
+	return ""
+}
+
+func redact(s string) string {
+	if len(s) <= 6 {
+		return "****"
+	}
+	return s[:6] + "****"
+}
+
+// scanString checks one value against the known formats and, when
+// checkEntropy is set, for high-entropy tokens.
+func scanString(path, value string, checkEntropy bool) []SecretFinding {
+	var findings []SecretFinding
+	for _, rule := range secretRules {
+		if match := rule.pattern.FindString(value); match != "" {
+			findings = append(findings, SecretFinding{Path: path, Rule: rule.name, Redacted: redact(match)})
+		}
+	}
+	if len(findings) == 0 && checkEntropy {
+		if token := highEntropyToken(value); token != "" {
+			findings = append(findings, SecretFinding{Path: path, Rule: "high-entropy-string", Redacted: redact(token)})
+		}
+	}
+	return findings
+}
+
+// scanTree walks a decoded JSON document. entropyAt decides which paths hold
+// values; names, descriptions and icon URLs are only checked for known formats.
+func scanTree(path string, node any, entropyAt func(path string) bool) []SecretFinding {
+	var findings []SecretFinding
+	switch v := node.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			child := k
+			if path != "" {
+				child = path + "." + k
+			}
+			findings = append(findings, scanTree(child, v[k], entropyAt)...)
+		}
+	case []any:
+		for i, item := range v {
+			findings = append(findings, scanTree(fmt.Sprintf("%s[%d]", path, i), item, entropyAt)...)
+		}
+	case string:
+		findings = append(findings, scanString(path, v, entropyAt(path))...)
+	}
+	return findings
+}
+
+func scanJSON(v any, entropyAt func(path string) bool) ([]SecretFinding, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var tree any
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, err
+	}
+	return scanTree("", tree, entropyAt), nil
+}
+
+var (
+	inputValuePath  = regexp.MustCompile(`\.(value|default)$`)
+	outputValuePath = regexp.MustCompile(`^(env\[\d+\]\.value|remote\.headers\..+|command\[\d+\])$`)
+)
+
+// ScanServerDetailSecrets scans the registry input. Values and defaults are
+// checked for high entropy; every string is checked for known token formats.
+func ScanServerDetailSecrets(serverDetail ServerDetail) ([]SecretFinding, error) {
+	return scanJSON(serverDetail, inputValuePath.MatchString)
+}
+
+// ScanCatalogSecrets scans an emitted catalog server. Env values, header
+// values and command arguments are checked for high entropy; every string is
+// checked for known token formats.
+func ScanCatalogSecrets(server *catalog.Server) ([]SecretFinding, error) {
+	return scanJSON(server, outputValuePath.MatchString)
+}
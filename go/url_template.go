@@ -0,0 +1,126 @@
+package catalogs
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// urlVariablePattern matches {variable} references in a registry URL template
+var urlVariablePattern = regexp.MustCompile(`\{([A-Za-z0-9_.-]+)\}`)
+
+// sampleValue picks a value to render a variable with when validating a
+// template: its default, its first choice, or a generic sample
+func sampleValue(input model.Input) string {
+	switch {
+	case input.Default != "":
+		return input.Default
+	case len(input.Choices) > 0:
+		return input.Choices[0]
+	case input.Format == model.FormatNumber:
+		return "1"
+	default:
+		return "sample"
+	}
+}
+
+// renderURLTemplate replaces {variable} references with values
+func renderURLTemplate(template string, values map[string]string) string {
+	return urlVariablePattern.ReplaceAllStringFunc(template, func(match string) string {
+		name := match[1 : len(match)-1]
+		if value, ok := values[name]; ok {
+			return value
+		}
+		return match
+	})
+}
+
+// validateURLTemplate checks that every {variable} in a remote URL is declared
+// and that the URL is well formed when rendered with sample values
+func validateURLTemplate(template string, variables map[string]model.Input) error {
+	var undeclared []string
+	for _, match := range urlVariablePattern.FindAllStringSubmatch(template, -1) {
+		if _, ok := variables[match[1]]; !ok {
+			undeclared = append(undeclared, match[1])
+		}
+	}
+	if len(undeclared) > 0 {
+		sort.Strings(undeclared)
+		return fmt.Errorf("remote URL %s references undeclared variables: %s", template, strings.Join(undeclared, ", "))
+	}
+
+	samples := make(map[string]string, len(variables))
+	for name, input := range variables {
+		samples[name] = sampleValue(input)
+	}
+	rendered := renderURLTemplate(template, samples)
+	if _, err := NormalizeRemoteURL(rendered); err != nil {
+		return fmt.Errorf("remote URL %s is malformed for sample values (%s): %w", template, rendered, err)
+	}
+	return nil
+}
+
+// templateValues are the values a publisher suggests for a URL variable: its
+// default and its choices
+func templateValues(input model.Input) []string {
+	var values []string
+	if input.Default != "" {
+		values = append(values, input.Default)
+	}
+	for _, choice := range input.Choices {
+		if choice != input.Default {
+			values = append(values, choice)
+		}
+	}
+	return values
+}
+
+// checkURLTemplate runs CheckRemoteURL on a remote URL template rendered once
+// for each default and choice of its variables, the other variables holding
+// a sample value. Problems the all-sample rendering has are left out: the
+// catalog URL check reports those already.
+func checkURLTemplate(template string, variables map[string]model.Input) []RemoteURLProblem {
+	if len(variables) == 0 {
+		return nil
+	}
+	samples := make(map[string]string, len(variables))
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		samples[name] = "sample"
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	known := make(map[string]bool)
+	for _, problem := range CheckRemoteURL(renderURLTemplate(template, samples)) {
+		known[problem.Reason] = true
+	}
+
+	var problems []RemoteURLProblem
+	for _, name := range names {
+		for _, value := range templateValues(variables[name]) {
+			values := make(map[string]string, len(samples))
+			for k, v := range samples {
+				values[k] = v
+			}
+			values[name] = value
+			for _, problem := range CheckRemoteURL(renderURLTemplate(template, values)) {
+				if known[problem.Reason] {
+					continue
+				}
+				problem.Reason = fmt.Sprintf("%s when %s is %q", problem.Reason, name, value)
+				problems = append(problems, problem)
+			}
+		}
+	}
+	return problems
+}
+
+// withSamplePlaceholders replaces catalog interpolation ({{config}},
+// ${SECRET}) with a neutral value so that templated URLs can be parsed
+func withSamplePlaceholders(s string) string {
+	return placeholderPattern.ReplaceAllString(s, "sample")
+}
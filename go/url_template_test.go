@@ -0,0 +1,146 @@
+package catalogs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+func TestTransformRemoteURLTemplate(t *testing.T) {
+	registryJSON := `{
+		"server": {
+			"name": "com.example/tenant-mcp",
+			"description": "Multi-tenant server",
+			"version": "1.0.0",
+			"remotes": [
+				{
+					"type": "streamable-http",
+					"url": "https://{tenant}.example.com/mcp/{region}?key={api_key}",
+					"variables": {
+						"tenant": {"description": "Tenant subdomain", "isRequired": true},
+						"region": {"description": "Region", "choices": ["us", "eu"]},
+						"api_key": {"description": "API key", "isSecret": true}
+					}
+				}
+			]
+		}
+	}`
+
+	result, err := TransformJSONWithOptions(registryJSON, TransformOptions{RemoteURLs: RemoteURLDeny})
+	if err != nil {
+		t.Fatalf("TransformJSONWithOptions failed: %v", err)
+	}
+
+	expectedURL := "https://{{tenant}}.example.com/mcp/{{region}}?key=${API_KEY}"
+	if result.Server.Remote.URL != expectedURL {
+		t.Errorf("Expected URL '%s', got '%s'", expectedURL, result.Server.Remote.URL)
+	}
+
+	// URL variables become config and secrets
+	if len(result.Server.Config) == 0 {
+		t.Fatal("Expected config for URL variables")
+	}
+	properties := result.Server.Config[0].(map[string]any)["properties"].(map[string]any)
+	if _, ok := properties["tenant"]; !ok {
+		t.Error("Expected tenant in config properties")
+	}
+	if _, ok := properties["region"]; !ok {
+		t.Error("Expected region in config properties")
+	}
+	if len(result.Server.Secrets) != 1 || result.Server.Secrets[0].Env != "API_KEY" {
+		t.Errorf("Expected API_KEY secret, got %+v", result.Server.Secrets)
+	}
+}
+
+func TestTransformRemoteURLTemplateUndeclared(t *testing.T) {
+	registryJSON := `{
+		"server": {
+			"name": "com.example/tenant-mcp",
+			"description": "Multi-tenant server",
+			"version": "1.0.0",
+			"remotes": [
+				{
+					"type": "streamable-http",
+					"url": "https://{tenant}.example.com/{path}",
+					"variables": {"tenant": {"description": "Tenant subdomain"}}
+				}
+			]
+		}
+	}`
+
+	_, err := TransformJSON(registryJSON)
+	if err == nil || !strings.Contains(err.Error(), "undeclared variables: path") {
+		t.Errorf("Expected undeclared variable error, got %v", err)
+	}
+}
+
+func TestValidateURLTemplateMalformed(t *testing.T) {
+	err := validateURLTemplate("{host}/mcp", map[string]model.Input{"host": {Default: "example.com"}})
+	if err == nil {
+		t.Error("Expected a URL without scheme to be rejected")
+	}
+
+	err = validateURLTemplate("https://{host}/mcp", map[string]model.Input{"host": {Default: "example.com"}})
+	if err != nil {
+		t.Errorf("Expected rendered URL to be valid: %v", err)
+	}
+}
+
+func TestTransformRemoteURLTemplateUnsafeValues(t *testing.T) {
+	tests := []struct {
+		name      string
+		variables string
+		reason    string
+	}{
+		{
+			name:      "default",
+			variables: `{"host": {"description": "Host", "default": "localhost"}}`,
+			reason:    `targets localhost when host is "localhost"`,
+		},
+		{
+			name:      "choice",
+			variables: `{"host": {"description": "Host", "choices": ["mcp.example.com", "169.254.169.254"]}}`,
+			reason:    `targets a link-local address when host is "169.254.169.254"`,
+		},
+		{
+			name:      "private default",
+			variables: `{"host": {"description": "Host", "default": "10.0.0.1"}}`,
+			reason:    `targets a private (RFC 1918) address when host is "10.0.0.1"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registryJSON := `{
+				"server": {
+					"name": "com.example/tenant-mcp",
+					"description": "Multi-tenant server",
+					"version": "1.0.0",
+					"remotes": [
+						{"type": "streamable-http", "url": "https://{host}/mcp", "variables": ` + tt.variables + `}
+					]
+				}
+			}`
+
+			_, err := TransformJSONWithOptions(registryJSON, TransformOptions{RemoteURLs: RemoteURLDeny})
+			if err == nil || !strings.Contains(err.Error(), tt.reason) {
+				t.Errorf("Expected %q, got %v", tt.reason, err)
+			}
+		})
+	}
+}
+
+func TestCheckURLTemplateSampleOnly(t *testing.T) {
+	// Variables without defaults or choices only hold a sample value, which
+	// the catalog URL check covers
+	problems := checkURLTemplate("http://{tenant}.example.com/mcp", map[string]model.Input{"tenant": {}})
+	if len(problems) != 0 {
+		t.Errorf("Expected no extra problems, got %v", problems)
+	}
+
+	problems = checkURLTemplate("https://{tenant}.example.com/mcp", map[string]model.Input{"tenant": {Default: "acme"}})
+	if len(problems) != 0 {
+		t.Errorf("Expected a safe default to pass, got %v", problems)
+	}
+}
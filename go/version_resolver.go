@@ -0,0 +1,266 @@
+package catalogs
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	v0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// Version is a parsed semantic version. Build metadata is ignored.
+type Version struct {
+	Major, Minor, Patch int
+	Prerelease          []string
+}
+
+var semverPattern = regexp.MustCompile(`^v?(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+
+// ParseVersion parses a semantic version with an optional v prefix
+func ParseVersion(s string) (Version, bool) {
+	match := semverPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if match == nil {
+		return Version{}, false
+	}
+	var v Version
+	v.Major, _ = strconv.Atoi(match[1])
+	v.Minor, _ = strconv.Atoi(match[2])
+	v.Patch, _ = strconv.Atoi(match[3])
+	if match[4] != "" {
+		v.Prerelease = strings.Split(match[4], ".")
+	}
+	return v, true
+}
+
+// Stable reports whether the version has no prerelease part
+func (v Version) Stable() bool {
+	return len(v.Prerelease) == 0
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if len(v.Prerelease) > 0 {
+		s += "-" + strings.Join(v.Prerelease, ".")
+	}
+	return s
+}
+
+// Compare returns -1, 0 or 1 following semver precedence
+func (v Version) Compare(other Version) int {
+	for _, d := range []int{v.Major - other.Major, v.Minor - other.Minor, v.Patch - other.Patch} {
+		if d != 0 {
+			return sign(d)
+		}
+	}
+	switch {
+	case v.Stable() && other.Stable():
+		return 0
+	case v.Stable():
+		return 1
+	case other.Stable():
+		return -1
+	}
+	for i := 0; i < len(v.Prerelease) && i < len(other.Prerelease); i++ {
+		if c := comparePrerelease(v.Prerelease[i], other.Prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	return sign(len(v.Prerelease) - len(other.Prerelease))
+}
+
+// comparePrerelease compares identifiers: numeric ones numerically and
+// below alphanumeric ones
+func comparePrerelease(a, b string) int {
+	an, aErr := strconv.Atoi(a)
+	bn, bErr := strconv.Atoi(b)
+	switch {
+	case aErr == nil && bErr == nil:
+		return sign(an - bn)
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+// VersionSelector picks a version: "latest", "stable" (latest without a
+// prerelease) or a constraint such as ^1.2, ~1.2.3, >=1.0.0 or 1.4.0
+type VersionSelector struct {
+	raw string
+	// matches is nil for latest and stable
+	matches func(Version) bool
+}
+
+// constraintOperators are checked in order, so two character operators first
+var constraintOperators = []string{">=", "<=", "^", "~", "=", ">", "<"}
+
+// ParseVersionSelector parses a -version flag value
+func ParseVersionSelector(s string) (VersionSelector, error) {
+	s = strings.TrimSpace(s)
+	switch s {
+	case "", "latest":
+		return VersionSelector{raw: "latest"}, nil
+	case "stable":
+		return VersionSelector{raw: "stable"}, nil
+	}
+
+	var op string
+	for _, candidate := range constraintOperators {
+		if strings.HasPrefix(s, candidate) {
+			op = candidate
+			break
+		}
+	}
+	rest := strings.TrimPrefix(s, op)
+	base, err := parseConstraintVersion(rest)
+	if err != nil {
+		return VersionSelector{}, fmt.Errorf("invalid version constraint %q: %w", s, err)
+	}
+
+	selector := VersionSelector{raw: s}
+	switch op {
+	case "^":
+		// Like npm, the first non-zero part given may not change: ^0.2.3
+		// means <0.3.0, ^0.0.3 <0.0.4, while ^0.0 means <0.1.0 and ^0 <1.0.0
+		parts := strings.Count(strings.SplitN(rest, "-", 2)[0], ".") + 1
+		var upper Version
+		switch {
+		case base.Major > 0 || parts == 1:
+			upper = Version{Major: base.Major + 1}
+		case base.Minor > 0 || parts == 2:
+			upper = Version{Minor: base.Minor + 1}
+		default:
+			upper = Version{Patch: base.Patch + 1}
+		}
+		selector.matches = func(v Version) bool { return v.Compare(base) >= 0 && v.Compare(upper) < 0 }
+	case "~":
+		upper := Version{Major: base.Major, Minor: base.Minor + 1}
+		selector.matches = func(v Version) bool { return v.Compare(base) >= 0 && v.Compare(upper) < 0 }
+	case "", "=":
+		selector.matches = func(v Version) bool { return v.Compare(base) == 0 }
+	case ">=":
+		selector.matches = func(v Version) bool { return v.Compare(base) >= 0 }
+	case ">":
+		selector.matches = func(v Version) bool { return v.Compare(base) > 0 }
+	case "<=":
+		selector.matches = func(v Version) bool { return v.Compare(base) <= 0 }
+	case "<":
+		selector.matches = func(v Version) bool { return v.Compare(base) < 0 }
+	}
+
+	// Like npm, prereleases only match when the constraint names a
+	// prerelease of the same version
+	matches := selector.matches
+	selector.matches = func(v Version) bool {
+		if !v.Stable() && (base.Stable() || v.Major != base.Major || v.Minor != base.Minor || v.Patch != base.Patch) {
+			return false
+		}
+		return matches(v)
+	}
+	return selector, nil
+}
+
+// parseConstraintVersion accepts partial versions like 1 or 1.2
+func parseConstraintVersion(s string) (Version, error) {
+	if parts := strings.Split(strings.TrimPrefix(s, "v"), "."); len(parts) < 3 && !strings.Contains(s, "-") {
+		for len(parts) < 3 {
+			parts = append(parts, "0")
+		}
+		s = strings.Join(parts, ".")
+	}
+	v, ok := ParseVersion(s)
+	if !ok {
+		return Version{}, fmt.Errorf("%q is not a semantic version", s)
+	}
+	return v, nil
+}
+
+func (s VersionSelector) String() string {
+	return s.raw
+}
+
+// ResolveVersion picks one of the versions of a server. Versions are ordered
+// by semver; when some are not semver, latest falls back to the publish date.
+// Constraints only match semver versions, and prereleases only when the
+// constraint names one. Non-semver versions are reported as warnings.
+func ResolveVersion(versions []v0.ServerResponse, selector VersionSelector) (*v0.ServerResponse, []string, error) {
+	if len(versions) == 0 {
+		return nil, nil, fmt.Errorf("no versions to choose from")
+	}
+
+	type candidate struct {
+		response *v0.ServerResponse
+		version  Version
+		semver   bool
+	}
+	var candidates []candidate
+	var warnings []string
+	allSemver := true
+	for i := range versions {
+		raw := versions[i].Server.Version
+		version, ok := ParseVersion(raw)
+		if !ok {
+			allSemver = false
+			warnings = append(warnings, fmt.Sprintf("server %s version %q is not a semantic version", versions[i].Server.Name, raw))
+		}
+		candidates = append(candidates, candidate{response: &versions[i], version: version, semver: ok})
+	}
+
+	publishedAt := func(c candidate) int64 {
+		if official := c.response.Meta.Official; official != nil {
+			return official.PublishedAt.UnixNano()
+		}
+		return 0
+	}
+	newest := func(filtered []candidate, bySemver bool) *v0.ServerResponse {
+		sort.SliceStable(filtered, func(i, j int) bool {
+			if bySemver {
+				return filtered[i].version.Compare(filtered[j].version) > 0
+			}
+			return publishedAt(filtered[i]) > publishedAt(filtered[j])
+		})
+		return filtered[0].response
+	}
+
+	name := versions[0].Server.Name
+	switch {
+	case selector.raw == "stable":
+		var stable []candidate
+		for _, c := range candidates {
+			if c.semver && c.version.Stable() {
+				stable = append(stable, c)
+			}
+		}
+		if len(stable) > 0 {
+			return newest(stable, true), warnings, nil
+		}
+		warnings = append(warnings, fmt.Sprintf("server %s has no stable semantic version, using the most recently published", name))
+		return newest(candidates, false), warnings, nil
+	case selector.matches == nil:
+		return newest(candidates, allSemver), warnings, nil
+	}
+
+	var matching []candidate
+	for _, c := range candidates {
+		if c.semver && selector.matches(c.version) {
+			matching = append(matching, c)
+		}
+	}
+	if len(matching) == 0 {
+		return nil, warnings, fmt.Errorf("no version of %s matches %s", name, selector)
+	}
+	return newest(matching, true), warnings, nil
+}
@@ -0,0 +1,157 @@
+package catalogs
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	v0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+		ok   bool
+	}{
+		{"1.2.3", "1.2.3", true},
+		{"v0.1.0", "0.1.0", true},
+		{"2.0.0-beta.1+build.5", "2.0.0-beta.1", true},
+		{"1.2", "", false},
+		{"latest", "", false},
+		{"2025-01-01", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseVersion(tt.raw)
+		if ok != tt.ok || (ok && got.String() != tt.want) {
+			t.Errorf("ParseVersion(%q) = %s, %t; want %s, %t", tt.raw, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	ordered := []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.1.0", "2.0.0"}
+	for i := 0; i+1 < len(ordered); i++ {
+		a, _ := ParseVersion(ordered[i])
+		b, _ := ParseVersion(ordered[i+1])
+		if a.Compare(b) != -1 || b.Compare(a) != 1 {
+			t.Errorf("Expected %s < %s", ordered[i], ordered[i+1])
+		}
+	}
+}
+
+func versionsOf(versions ...string) []v0.ServerResponse {
+	published := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var responses []v0.ServerResponse
+	for i, version := range versions {
+		responses = append(responses, v0.ServerResponse{
+			Server: v0.ServerJSON{Name: "com.example/weather", Version: version},
+			Meta: v0.ResponseMeta{Official: &v0.RegistryExtensions{
+				Status:      "active",
+				PublishedAt: published.AddDate(0, 0, i),
+			}},
+		})
+	}
+	return responses
+}
+
+func TestResolveVersion(t *testing.T) {
+	versions := versionsOf("1.2.0", "1.10.0", "2.0.0-rc.1", "1.2.5", "0.9.0")
+
+	tests := []struct {
+		selector string
+		want     string
+	}{
+		{"latest", "2.0.0-rc.1"},
+		{"stable", "1.10.0"},
+		{"^1.2", "1.10.0"},
+		{"~1.2", "1.2.5"},
+		{"1.2.0", "1.2.0"},
+		{"<1.0.0", "0.9.0"},
+		{"^0.9", "0.9.0"},
+	}
+
+	for _, tt := range tests {
+		selector, err := ParseVersionSelector(tt.selector)
+		if err != nil {
+			t.Errorf("ParseVersionSelector(%q) failed: %v", tt.selector, err)
+			continue
+		}
+		got, warnings, err := ResolveVersion(versions, selector)
+		if err != nil {
+			t.Errorf("ResolveVersion(%s) failed: %v", tt.selector, err)
+			continue
+		}
+		if got.Server.Version != tt.want {
+			t.Errorf("ResolveVersion(%s) = %s, want %s", tt.selector, got.Server.Version, tt.want)
+		}
+		if len(warnings) > 0 {
+			t.Errorf("Expected no warnings for semver versions, got %v", warnings)
+		}
+	}
+
+	selector, _ := ParseVersionSelector("^3")
+	if _, _, err := ResolveVersion(versions, selector); err == nil {
+		t.Error("Expected no version to match ^3")
+	}
+}
+
+func TestVersionSelectorCaretZero(t *testing.T) {
+	tests := []struct {
+		selector string
+		version  string
+		want     bool
+	}{
+		{"^0.0.3", "0.0.3", true},
+		{"^0.0.3", "0.0.4", false},
+		{"^0.0.3", "0.1.0", false},
+		{"^0.2.3", "0.2.9", true},
+		{"^0.2.3", "0.3.0", false},
+		{"^0.0", "0.0.9", true},
+		{"^0.0", "0.1.0", false},
+		{"^0", "0.9.0", true},
+		{"^0", "1.0.0", false},
+	}
+
+	for _, tt := range tests {
+		selector, err := ParseVersionSelector(tt.selector)
+		if err != nil {
+			t.Fatalf("ParseVersionSelector(%q) failed: %v", tt.selector, err)
+		}
+		version, _ := ParseVersion(tt.version)
+		if got := selector.matches(version); got != tt.want {
+			t.Errorf("%s matches %s = %t, want %t", tt.selector, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestResolveVersionPublishDateFallback(t *testing.T) {
+	// Later entries are published later
+	versions := versionsOf("2.0.0", "2025.06", "1.0.0")
+
+	latest, _ := ParseVersionSelector("latest")
+	got, warnings, err := ResolveVersion(versions, latest)
+	if err != nil {
+		t.Fatalf("ResolveVersion failed: %v", err)
+	}
+	if got.Server.Version != "1.0.0" {
+		t.Errorf("Expected the most recently published version, got %s", got.Server.Version)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], `"2025.06" is not a semantic version`) {
+		t.Errorf("Expected a non-semver warning, got %v", warnings)
+	}
+
+	stable, _ := ParseVersionSelector("stable")
+	if got, _, _ := ResolveVersion(versions, stable); got.Server.Version != "2.0.0" {
+		t.Errorf("Expected the highest stable semver, got %s", got.Server.Version)
+	}
+}
+
+func TestParseVersionSelectorInvalid(t *testing.T) {
+	for _, s := range []string{"^one", "newest", "~"} {
+		if _, err := ParseVersionSelector(s); err == nil {
+			t.Errorf("Expected %q to be rejected", s)
+		}
+	}
+}